@@ -0,0 +1,13 @@
+package recaptcha_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCaptcha(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Captcha Suite")
+}
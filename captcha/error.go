@@ -0,0 +1,80 @@
+package recaptcha
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorKind tells who is responsible for a failed verification
+type ErrorKind int
+
+const (
+	// ErrorKindUser is the kind of errors caused by the token the user sent
+	ErrorKindUser ErrorKind = iota
+	// ErrorKindConfig is the kind of errors caused by a misconfiguration,
+	// e.g. an invalid secret
+	ErrorKindConfig
+	// ErrorKindTransport is the kind of errors caused by failed requests to
+	// the provider or malformed responses
+	ErrorKindTransport
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindUser:
+		return "user"
+	case ErrorKindConfig:
+		return "config"
+	case ErrorKindTransport:
+		return "transport"
+	default:
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+}
+
+// Error is a failed captcha verification. User errors match ErrInvalidCaptcha
+// with errors.Is.
+type Error struct {
+	Kind ErrorKind
+	// Reasons are the reasons of user errors
+	Reasons []Reason
+	// ErrorCodes are the raw error codes of configuration errors
+	ErrorCodes []string
+	// Err is the underlying error of transport errors
+	Err error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+
+	b.WriteString("captcha ")
+	b.WriteString(e.Kind.String())
+	b.WriteString(" error")
+
+	switch {
+	case e.Err != nil:
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	case len(e.ErrorCodes) > 0:
+		b.WriteString(": ")
+		b.WriteString(strings.Join(e.ErrorCodes, ", "))
+	case len(e.Reasons) > 0:
+		b.WriteString(": ")
+		for i, r := range e.Reasons {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(string(r))
+		}
+	}
+
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return target == ErrInvalidCaptcha && e.Kind == ErrorKindUser
+}
@@ -0,0 +1,67 @@
+package recaptcha
+
+import (
+	"context"
+	"net"
+	"slices"
+)
+
+// Fake verifies tokens without calling a provider, for local development and
+// CI where no secret is available. Never use it in production.
+type Fake struct {
+	acceptAll bool
+	tokens    []string
+}
+
+// AcceptAll returns a fake accepting every non-empty token
+func AcceptAll() *Fake {
+	return &Fake{
+		acceptAll: true,
+	}
+}
+
+// RejectAll returns a fake rejecting every token
+func RejectAll() *Fake {
+	return &Fake{}
+}
+
+// AcceptTokens returns a fake accepting only the given test tokens
+func AcceptTokens(tokens ...string) *Fake {
+	return &Fake{
+		tokens: tokens,
+	}
+}
+
+func (f *Fake) Verify(
+	_ context.Context,
+	token string,
+	_ net.IP,
+) (*Result, error) {
+	if token == "" {
+		return &Result{
+			Reasons: []Reason{ReasonMissingInput},
+		}, nil
+	}
+
+	if !f.acceptAll && !slices.Contains(f.tokens, token) {
+		return &Result{
+			Reasons: []Reason{ReasonInvalidInput},
+		}, nil
+	}
+
+	return &Result{
+		Success: true,
+	}, nil
+}
+
+func (f *Fake) Validate(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (bool, error) {
+	return AsValidator(f).Validate(ctx, token, clientIP)
+}
+
+var _ Verifier = (*Fake)(nil)
+
+var _ Validator = (*Fake)(nil)
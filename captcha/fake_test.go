@@ -0,0 +1,36 @@
+package recaptcha_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+)
+
+var _ = Describe("Fake", func() {
+	ctx := context.Background()
+
+	It("should accept all non-empty tokens", func() {
+		Expect(recaptcha.AcceptAll().Validate(ctx, "anything", nil)).To(BeTrue())
+
+		res, err := recaptcha.AcceptAll().Verify(ctx, "", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonMissingInput}))
+	})
+
+	It("should reject all tokens", func() {
+		Expect(recaptcha.RejectAll().Validate(ctx, "anything", nil)).To(BeFalse())
+	})
+
+	It("should accept only test tokens", func() {
+		f := recaptcha.AcceptTokens("pass-1", "pass-2")
+
+		Expect(f.Validate(ctx, "pass-2", nil)).To(BeTrue())
+
+		res, err := f.Verify(ctx, "fail", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeFalse())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonInvalidInput}))
+	})
+})
@@ -0,0 +1,221 @@
+package recaptcha
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FailurePolicy decides about tokens which cannot be verified because the
+// provider is unavailable
+type FailurePolicy int
+
+const (
+	// FailClosed rejects tokens while the provider is unavailable
+	FailClosed FailurePolicy = iota
+	// FailOpen accepts tokens while the provider is unavailable
+	FailOpen
+)
+
+// FallbackConfig configures a FallbackVerifier
+type FallbackConfig struct {
+	Policy FailurePolicy
+	// FailureThreshold is the number of consecutive transport errors opening
+	// the circuit, 0 disables circuit breaking
+	FailureThreshold int
+	// OpenDuration is the duration the provider is not called for once the
+	// circuit opened. Afterwards the circuit is half-open: a single trial
+	// verification is let through, which closes the circuit on success and
+	// opens it again on failure, while concurrent verifications fall back.
+	OpenDuration time.Duration
+}
+
+// FallbackStats counts the fallbacks of a FallbackVerifier
+type FallbackStats struct {
+	// Failures is the number of transport errors of the provider
+	Failures int64
+	// Fallbacks is the number of results decided by the failure policy
+	Fallbacks int64
+	// CircuitOpens is the number of times the circuit opened
+	CircuitOpens int64
+	// CircuitOpen reports whether the circuit is currently open
+	CircuitOpen bool
+}
+
+// FallbackVerifier decides about tokens by a failure policy when the wrapped
+// verifier fails with a transport error, and stops calling it for a while
+// after repeated failures. Configuration errors are returned as is, as they
+// need to be fixed instead of being worked around. Errors not of type *Error
+// are treated as transport errors. Wrap Validators with FromValidator.
+type FallbackVerifier struct {
+	v   Verifier
+	cfg FallbackConfig
+
+	mu       sync.Mutex
+	failures int
+	// openUntil is zero while the circuit is closed
+	openUntil time.Time
+	// trial reports whether the trial verification of the half-open circuit
+	// is in flight
+	trial bool
+
+	failuresTotal atomic.Int64
+	fallbacks     atomic.Int64
+	circuitOpens  atomic.Int64
+}
+
+func NewFallbackVerifier(v Verifier, cfg FallbackConfig) *FallbackVerifier {
+	return &FallbackVerifier{
+		v:   v,
+		cfg: cfg,
+	}
+}
+
+func (f *FallbackVerifier) Verify(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (*Result, error) {
+	ok, trial := f.admit()
+	if !ok {
+		return f.fallback(), nil
+	}
+
+	res, err := f.v.Verify(ctx, token, clientIP)
+
+	var cErr *Error
+	if err != nil && errors.As(err, &cErr) && cErr.Kind != ErrorKindTransport {
+		f.release(trial)
+
+		return nil, err
+	}
+
+	// canceled requests tell nothing about the provider
+	if err != nil && ctx.Err() != nil {
+		f.release(trial)
+
+		return nil, err
+	}
+
+	if err != nil {
+		f.failed(trial)
+
+		return f.fallback(), nil
+	}
+
+	f.succeeded(trial)
+
+	return res, nil
+}
+
+// admit reports whether the provider may be called and whether the call is
+// the trial of the half-open circuit
+func (f *FallbackVerifier) admit() (bool, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.openUntil.IsZero() {
+		return true, false
+	}
+
+	if f.trial || time.Now().Before(f.openUntil) {
+		return false, false
+	}
+
+	f.trial = true
+
+	return true, true
+}
+
+func (f *FallbackVerifier) isOpen() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return !f.openUntil.IsZero() && (f.trial || time.Now().Before(f.openUntil))
+}
+
+// release lets the next verification be the trial again, if the trial told
+// nothing about the availability of the provider
+func (f *FallbackVerifier) release(trial bool) {
+	if !trial {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.trial = false
+}
+
+func (f *FallbackVerifier) failed(trial bool) {
+	f.failuresTotal.Add(1)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures++
+
+	// failures of calls admitted before the circuit opened do not extend it
+	if !trial && !f.openUntil.IsZero() {
+		return
+	}
+
+	if f.cfg.FailureThreshold > 0 && f.failures >= f.cfg.FailureThreshold {
+		f.openUntil = time.Now().Add(f.cfg.OpenDuration)
+		f.trial = false
+		f.circuitOpens.Add(1)
+	}
+}
+
+func (f *FallbackVerifier) succeeded(trial bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures = 0
+
+	if trial {
+		f.openUntil = time.Time{}
+		f.trial = false
+	}
+}
+
+func (f *FallbackVerifier) fallback() *Result {
+	f.fallbacks.Add(1)
+
+	if f.cfg.Policy == FailOpen {
+		return &Result{
+			Success:  true,
+			Fallback: true,
+		}
+	}
+
+	return &Result{
+		Reasons:  []Reason{ReasonUnavailable},
+		Fallback: true,
+	}
+}
+
+// Stats returns a snapshot of the fallback counters, e.g. to export as metrics
+func (f *FallbackVerifier) Stats() FallbackStats {
+	return FallbackStats{
+		Failures:     f.failuresTotal.Load(),
+		Fallbacks:    f.fallbacks.Load(),
+		CircuitOpens: f.circuitOpens.Load(),
+		CircuitOpen:  f.isOpen(),
+	}
+}
+
+func (f *FallbackVerifier) Validate(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (bool, error) {
+	return AsValidator(f).Validate(ctx, token, clientIP)
+}
+
+var _ Verifier = (*FallbackVerifier)(nil)
+
+var _ Validator = (*FallbackVerifier)(nil)
@@ -0,0 +1,185 @@
+package recaptcha_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+)
+
+var _ = Describe("FallbackVerifier", func() {
+	ctx := context.Background()
+
+	var (
+		calls int
+		err   error
+	)
+
+	provider := verifierFunc(func(_ context.Context, token string, _ net.IP) (*recaptcha.Result, error) {
+		calls++
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &recaptcha.Result{Success: token == "valid"}, nil
+	})
+
+	transportErr := &recaptcha.Error{
+		Kind: recaptcha.ErrorKindTransport,
+		Err:  errors.New("connection refused"),
+	}
+
+	BeforeEach(func() {
+		calls = 0
+		err = nil
+	})
+
+	It("should pass through results of an available provider", func() {
+		f := recaptcha.NewFallbackVerifier(provider, recaptcha.FallbackConfig{Policy: recaptcha.FailOpen})
+
+		Expect(f.Validate(ctx, "valid", nil)).To(BeTrue())
+		Expect(f.Validate(ctx, "invalid", nil)).To(BeFalse())
+		Expect(f.Stats()).To(Equal(recaptcha.FallbackStats{}))
+	})
+
+	It("should reject tokens on transport errors when failing closed", func() {
+		err = transportErr
+		f := recaptcha.NewFallbackVerifier(provider, recaptcha.FallbackConfig{Policy: recaptcha.FailClosed})
+
+		res, err := f.Verify(ctx, "valid", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeFalse())
+		Expect(res.Fallback).To(BeTrue())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonUnavailable}))
+	})
+
+	It("should accept tokens on transport errors when failing open", func() {
+		err = errors.New("unknown failure")
+		f := recaptcha.NewFallbackVerifier(provider, recaptcha.FallbackConfig{Policy: recaptcha.FailOpen})
+
+		res, err := f.Verify(ctx, "invalid", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeTrue())
+		Expect(res.Fallback).To(BeTrue())
+		Expect(f.Stats().Fallbacks).To(Equal(int64(1)))
+	})
+
+	It("should return configuration errors", func() {
+		err = &recaptcha.Error{Kind: recaptcha.ErrorKindConfig, ErrorCodes: []string{"invalid-input-secret"}}
+		f := recaptcha.NewFallbackVerifier(provider, recaptcha.FallbackConfig{Policy: recaptcha.FailOpen})
+
+		_, vErr := f.Verify(ctx, "valid", nil)
+		Expect(vErr).To(MatchError(err))
+		Expect(f.Stats().Fallbacks).To(BeZero())
+	})
+
+	It("should open the circuit after repeated failures and close it after a successful trial", func() {
+		err = transportErr
+		f := recaptcha.NewFallbackVerifier(provider, recaptcha.FallbackConfig{
+			Policy:           recaptcha.FailClosed,
+			FailureThreshold: 2,
+			OpenDuration:     20 * time.Millisecond,
+		})
+
+		for range 4 {
+			Expect(f.Validate(ctx, "valid", nil)).To(BeFalse())
+		}
+		Expect(calls).To(Equal(2))
+		Expect(f.Stats()).To(Equal(recaptcha.FallbackStats{
+			Failures:     2,
+			Fallbacks:    4,
+			CircuitOpens: 1,
+			CircuitOpen:  true,
+		}))
+
+		err = nil
+		Eventually(func() bool {
+			return f.Stats().CircuitOpen
+		}).Should(BeFalse())
+
+		Expect(f.Validate(ctx, "valid", nil)).To(BeTrue())
+		Expect(calls).To(Equal(3))
+		Expect(f.Stats().CircuitOpen).To(BeFalse())
+	})
+
+	It("should let a single trial through the half-open circuit", func() {
+		var (
+			trials  atomic.Int32
+			failing atomic.Bool
+		)
+		release := make(chan struct{})
+
+		failing.Store(true)
+		f := recaptcha.NewFallbackVerifier(verifierFunc(func(context.Context, string, net.IP) (*recaptcha.Result, error) {
+			if failing.Load() {
+				return nil, transportErr
+			}
+
+			trials.Add(1)
+			<-release
+
+			return &recaptcha.Result{Success: true}, nil
+		}), recaptcha.FallbackConfig{
+			Policy:           recaptcha.FailClosed,
+			FailureThreshold: 1,
+			OpenDuration:     10 * time.Millisecond,
+		})
+
+		Expect(f.Validate(ctx, "valid", nil)).To(BeFalse())
+		Expect(f.Stats().CircuitOpen).To(BeTrue())
+
+		failing.Store(false)
+		Eventually(func() bool {
+			return f.Stats().CircuitOpen
+		}).Should(BeFalse())
+
+		done := make(chan bool)
+		go func() {
+			defer GinkgoRecover()
+
+			ok, err := f.Validate(ctx, "valid", nil)
+			Expect(err).ToNot(HaveOccurred())
+			done <- ok
+		}()
+
+		Eventually(trials.Load).Should(Equal(int32(1)))
+		Expect(f.Stats().CircuitOpen).To(BeTrue())
+
+		res, err := f.Verify(ctx, "valid", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Fallback).To(BeTrue())
+
+		close(release)
+		Expect(<-done).To(BeTrue())
+		Expect(trials.Load()).To(Equal(int32(1)))
+		Expect(f.Stats().CircuitOpen).To(BeFalse())
+
+		Expect(f.Validate(ctx, "valid", nil)).To(BeTrue())
+		Expect(trials.Load()).To(Equal(int32(2)))
+	})
+
+	It("should open the circuit again after a failed trial", func() {
+		err = transportErr
+		f := recaptcha.NewFallbackVerifier(provider, recaptcha.FallbackConfig{
+			Policy:           recaptcha.FailClosed,
+			FailureThreshold: 1,
+			OpenDuration:     10 * time.Millisecond,
+		})
+
+		Expect(f.Validate(ctx, "valid", nil)).To(BeFalse())
+		Eventually(func() bool {
+			return f.Stats().CircuitOpen
+		}).Should(BeFalse())
+
+		Expect(f.Validate(ctx, "valid", nil)).To(BeFalse())
+		Expect(calls).To(Equal(2))
+		Expect(f.Stats().CircuitOpens).To(Equal(int64(2)))
+		Expect(f.Stats().CircuitOpen).To(BeTrue())
+	})
+})
@@ -22,7 +22,7 @@ func trimTrailingSlashes(u *url.URL) {
 type Invoker interface {
 	// Siteverify invokes siteverify operation.
 	//
-	// Validates a Google recpatcha request.
+	// Validates a Google recaptcha request.
 	//
 	// POST /api/siteverify
 	Siteverify(ctx context.Context, request *SiteverifyForm) (*SiteverifyResponse, error)
@@ -69,7 +69,7 @@ func (c *Client) requestURL(ctx context.Context) *url.URL {
 
 // Siteverify invokes siteverify operation.
 //
-// Validates a Google recpatcha request.
+// Validates a Google recaptcha request.
 //
 // POST /api/siteverify
 func (c *Client) Siteverify(ctx context.Context, request *SiteverifyForm) (*SiteverifyResponse, error) {
@@ -12,144 +12,145 @@ import (
 	"github.com/ogen-go/ogen/validate"
 )
 
-// Encode encodes SiteverifyResponseData as json.
-func (o OptSiteverifyResponseData) Encode(e *jx.Encoder) {
+// Encode encodes float64 as json.
+func (o OptFloat64) Encode(e *jx.Encoder) {
 	if !o.Set {
 		return
 	}
-	o.Value.Encode(e)
+	e.Float64(float64(o.Value))
 }
 
-// Decode decodes SiteverifyResponseData from json.
-func (o *OptSiteverifyResponseData) Decode(d *jx.Decoder) error {
+// Decode decodes float64 from json.
+func (o *OptFloat64) Decode(d *jx.Decoder) error {
 	if o == nil {
-		return errors.New("invalid: unable to decode OptSiteverifyResponseData to nil")
+		return errors.New("invalid: unable to decode OptFloat64 to nil")
 	}
 	o.Set = true
-	if err := o.Value.Decode(d); err != nil {
+	v, err := d.Float64()
+	if err != nil {
 		return err
 	}
+	o.Value = float64(v)
 	return nil
 }
 
 // MarshalJSON implements stdjson.Marshaler.
-func (s OptSiteverifyResponseData) MarshalJSON() ([]byte, error) {
+func (s OptFloat64) MarshalJSON() ([]byte, error) {
 	e := jx.Encoder{}
 	s.Encode(&e)
 	return e.Bytes(), nil
 }
 
 // UnmarshalJSON implements stdjson.Unmarshaler.
-func (s *OptSiteverifyResponseData) UnmarshalJSON(data []byte) error {
+func (s *OptFloat64) UnmarshalJSON(data []byte) error {
 	d := jx.DecodeBytes(data)
 	return s.Decode(d)
 }
 
-// Encode implements json.Marshaler.
-func (s *SiteverifyResponse) Encode(e *jx.Encoder) {
-	e.ObjStart()
-	s.encodeFields(e)
-	e.ObjEnd()
-}
-
-// encodeFields encodes fields.
-func (s *SiteverifyResponse) encodeFields(e *jx.Encoder) {
-	{
-		if s.Data.Set {
-			e.FieldStart("data")
-			s.Data.Encode(e)
-		}
+// Encode encodes string as json.
+func (o OptString) Encode(e *jx.Encoder) {
+	if !o.Set {
+		return
 	}
+	e.Str(string(o.Value))
 }
 
-var jsonFieldsNameOfSiteverifyResponse = [1]string{
-	0: "data",
-}
-
-// Decode decodes SiteverifyResponse from json.
-func (s *SiteverifyResponse) Decode(d *jx.Decoder) error {
-	if s == nil {
-		return errors.New("invalid: unable to decode SiteverifyResponse to nil")
+// Decode decodes string from json.
+func (o *OptString) Decode(d *jx.Decoder) error {
+	if o == nil {
+		return errors.New("invalid: unable to decode OptString to nil")
 	}
-
-	if err := d.ObjBytes(func(d *jx.Decoder, k []byte) error {
-		switch string(k) {
-		case "data":
-			if err := func() error {
-				s.Data.Reset()
-				if err := s.Data.Decode(d); err != nil {
-					return err
-				}
-				return nil
-			}(); err != nil {
-				return errors.Wrap(err, "decode field \"data\"")
-			}
-		default:
-			return d.Skip()
-		}
-		return nil
-	}); err != nil {
-		return errors.Wrap(err, "decode SiteverifyResponse")
+	o.Set = true
+	v, err := d.Str()
+	if err != nil {
+		return err
 	}
-
+	o.Value = string(v)
 	return nil
 }
 
 // MarshalJSON implements stdjson.Marshaler.
-func (s *SiteverifyResponse) MarshalJSON() ([]byte, error) {
+func (s OptString) MarshalJSON() ([]byte, error) {
 	e := jx.Encoder{}
 	s.Encode(&e)
 	return e.Bytes(), nil
 }
 
 // UnmarshalJSON implements stdjson.Unmarshaler.
-func (s *SiteverifyResponse) UnmarshalJSON(data []byte) error {
+func (s *OptString) UnmarshalJSON(data []byte) error {
 	d := jx.DecodeBytes(data)
 	return s.Decode(d)
 }
 
 // Encode implements json.Marshaler.
-func (s *SiteverifyResponseData) Encode(e *jx.Encoder) {
+func (s *SiteverifyResponse) Encode(e *jx.Encoder) {
 	e.ObjStart()
 	s.encodeFields(e)
 	e.ObjEnd()
 }
 
 // encodeFields encodes fields.
-func (s *SiteverifyResponseData) encodeFields(e *jx.Encoder) {
+func (s *SiteverifyResponse) encodeFields(e *jx.Encoder) {
 	{
 		e.FieldStart("success")
 		e.Bool(s.Success)
 	}
 	{
-		e.FieldStart("challenge_ts")
-		e.Str(s.ChallengeTs)
+		if s.ChallengeTs.Set {
+			e.FieldStart("challenge_ts")
+			s.ChallengeTs.Encode(e)
+		}
+	}
+	{
+		if s.Hostname.Set {
+			e.FieldStart("hostname")
+			s.Hostname.Encode(e)
+		}
+	}
+	{
+		if s.Score.Set {
+			e.FieldStart("score")
+			s.Score.Encode(e)
+		}
+	}
+	{
+		if s.Action.Set {
+			e.FieldStart("action")
+			s.Action.Encode(e)
+		}
 	}
 	{
-		e.FieldStart("hostname")
-		e.Str(s.Hostname)
+		if s.ApkPackageName.Set {
+			e.FieldStart("apk_package_name")
+			s.ApkPackageName.Encode(e)
+		}
 	}
 	{
-		e.FieldStart("error-codes")
-		e.ArrStart()
-		for _, elem := range s.ErrorMinusCodes {
-			e.Str(elem)
+		if s.ErrorMinusCodes != nil {
+			e.FieldStart("error-codes")
+			e.ArrStart()
+			for _, elem := range s.ErrorMinusCodes {
+				e.Str(elem)
+			}
+			e.ArrEnd()
 		}
-		e.ArrEnd()
 	}
 }
 
-var jsonFieldsNameOfSiteverifyResponseData = [4]string{
+var jsonFieldsNameOfSiteverifyResponse = [7]string{
 	0: "success",
 	1: "challenge_ts",
 	2: "hostname",
-	3: "error-codes",
+	3: "score",
+	4: "action",
+	5: "apk_package_name",
+	6: "error-codes",
 }
 
-// Decode decodes SiteverifyResponseData from json.
-func (s *SiteverifyResponseData) Decode(d *jx.Decoder) error {
+// Decode decodes SiteverifyResponse from json.
+func (s *SiteverifyResponse) Decode(d *jx.Decoder) error {
 	if s == nil {
-		return errors.New("invalid: unable to decode SiteverifyResponseData to nil")
+		return errors.New("invalid: unable to decode SiteverifyResponse to nil")
 	}
 	var requiredBitSet [1]uint8
 
@@ -168,11 +169,9 @@ func (s *SiteverifyResponseData) Decode(d *jx.Decoder) error {
 				return errors.Wrap(err, "decode field \"success\"")
 			}
 		case "challenge_ts":
-			requiredBitSet[0] |= 1 << 1
 			if err := func() error {
-				v, err := d.Str()
-				s.ChallengeTs = string(v)
-				if err != nil {
+				s.ChallengeTs.Reset()
+				if err := s.ChallengeTs.Decode(d); err != nil {
 					return err
 				}
 				return nil
@@ -180,19 +179,46 @@ func (s *SiteverifyResponseData) Decode(d *jx.Decoder) error {
 				return errors.Wrap(err, "decode field \"challenge_ts\"")
 			}
 		case "hostname":
-			requiredBitSet[0] |= 1 << 2
 			if err := func() error {
-				v, err := d.Str()
-				s.Hostname = string(v)
-				if err != nil {
+				s.Hostname.Reset()
+				if err := s.Hostname.Decode(d); err != nil {
 					return err
 				}
 				return nil
 			}(); err != nil {
 				return errors.Wrap(err, "decode field \"hostname\"")
 			}
+		case "score":
+			if err := func() error {
+				s.Score.Reset()
+				if err := s.Score.Decode(d); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return errors.Wrap(err, "decode field \"score\"")
+			}
+		case "action":
+			if err := func() error {
+				s.Action.Reset()
+				if err := s.Action.Decode(d); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return errors.Wrap(err, "decode field \"action\"")
+			}
+		case "apk_package_name":
+			if err := func() error {
+				s.ApkPackageName.Reset()
+				if err := s.ApkPackageName.Decode(d); err != nil {
+					return err
+				}
+				return nil
+			}(); err != nil {
+				return errors.Wrap(err, "decode field \"apk_package_name\"")
+			}
 		case "error-codes":
-			requiredBitSet[0] |= 1 << 3
 			if err := func() error {
 				s.ErrorMinusCodes = make([]string, 0)
 				if err := d.Arr(func(d *jx.Decoder) error {
@@ -216,12 +242,12 @@ func (s *SiteverifyResponseData) Decode(d *jx.Decoder) error {
 		}
 		return nil
 	}); err != nil {
-		return errors.Wrap(err, "decode SiteverifyResponseData")
+		return errors.Wrap(err, "decode SiteverifyResponse")
 	}
 	// Validate required fields.
 	var failures []validate.FieldError
 	for i, mask := range [1]uint8{
-		0b00001111,
+		0b00000001,
 	} {
 		if result := (requiredBitSet[i] & mask) ^ mask; result != 0 {
 			// Mask only required fields and check equality to mask using XOR.
@@ -233,8 +259,8 @@ func (s *SiteverifyResponseData) Decode(d *jx.Decoder) error {
 				bitIdx := bits.TrailingZeros8(result)
 				fieldIdx := i*8 + bitIdx
 				var name string
-				if fieldIdx < len(jsonFieldsNameOfSiteverifyResponseData) {
-					name = jsonFieldsNameOfSiteverifyResponseData[fieldIdx]
+				if fieldIdx < len(jsonFieldsNameOfSiteverifyResponse) {
+					name = jsonFieldsNameOfSiteverifyResponse[fieldIdx]
 				} else {
 					name = strconv.Itoa(fieldIdx)
 				}
@@ -255,14 +281,14 @@ func (s *SiteverifyResponseData) Decode(d *jx.Decoder) error {
 }
 
 // MarshalJSON implements stdjson.Marshaler.
-func (s *SiteverifyResponseData) MarshalJSON() ([]byte, error) {
+func (s *SiteverifyResponse) MarshalJSON() ([]byte, error) {
 	e := jx.Encoder{}
 	s.Encode(&e)
 	return e.Bytes(), nil
 }
 
 // UnmarshalJSON implements stdjson.Unmarshaler.
-func (s *SiteverifyResponseData) UnmarshalJSON(data []byte) error {
+func (s *SiteverifyResponse) UnmarshalJSON(data []byte) error {
 	d := jx.DecodeBytes(data)
 	return s.Decode(d)
 }
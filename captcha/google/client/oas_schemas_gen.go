@@ -2,38 +2,38 @@
 
 package client
 
-// NewOptSiteverifyResponseData returns new OptSiteverifyResponseData with value set to v.
-func NewOptSiteverifyResponseData(v SiteverifyResponseData) OptSiteverifyResponseData {
-	return OptSiteverifyResponseData{
+// NewOptFloat64 returns new OptFloat64 with value set to v.
+func NewOptFloat64(v float64) OptFloat64 {
+	return OptFloat64{
 		Value: v,
 		Set:   true,
 	}
 }
 
-// OptSiteverifyResponseData is optional SiteverifyResponseData.
-type OptSiteverifyResponseData struct {
-	Value SiteverifyResponseData
+// OptFloat64 is optional float64.
+type OptFloat64 struct {
+	Value float64
 	Set   bool
 }
 
-// IsSet returns true if OptSiteverifyResponseData was set.
-func (o OptSiteverifyResponseData) IsSet() bool { return o.Set }
+// IsSet returns true if OptFloat64 was set.
+func (o OptFloat64) IsSet() bool { return o.Set }
 
 // Reset unsets value.
-func (o *OptSiteverifyResponseData) Reset() {
-	var v SiteverifyResponseData
+func (o *OptFloat64) Reset() {
+	var v float64
 	o.Value = v
 	o.Set = false
 }
 
 // SetTo sets value to v.
-func (o *OptSiteverifyResponseData) SetTo(v SiteverifyResponseData) {
+func (o *OptFloat64) SetTo(v float64) {
 	o.Set = true
 	o.Value = v
 }
 
 // Get returns value and boolean that denotes whether value was set.
-func (o OptSiteverifyResponseData) Get() (v SiteverifyResponseData, ok bool) {
+func (o OptFloat64) Get() (v float64, ok bool) {
 	if !o.Set {
 		return v, false
 	}
@@ -41,7 +41,53 @@ func (o OptSiteverifyResponseData) Get() (v SiteverifyResponseData, ok bool) {
 }
 
 // Or returns value if set, or given parameter if does not.
-func (o OptSiteverifyResponseData) Or(d SiteverifyResponseData) SiteverifyResponseData {
+func (o OptFloat64) Or(d float64) float64 {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
+	return OptString{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
+func (o OptString) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptString) Reset() {
+	var v string
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptString) SetTo(v string) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptString) Get() (v string, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptString) Or(d string) string {
 	if v, ok := o.Get(); ok {
 		return v
 	}
@@ -50,9 +96,9 @@ func (o OptSiteverifyResponseData) Or(d SiteverifyResponseData) SiteverifyRespon
 
 // Ref: #/components/schemas/SiteverifyForm
 type SiteverifyForm struct {
-	Secret   string `json:"secret"`
-	Response string `json:"response"`
-	Remoteip string `json:"remoteip"`
+	Secret   string    `json:"secret"`
+	Response string    `json:"response"`
+	Remoteip OptString `json:"remoteip"`
 }
 
 // GetSecret returns the value of Secret.
@@ -66,7 +112,7 @@ func (s *SiteverifyForm) GetResponse() string {
 }
 
 // GetRemoteip returns the value of Remoteip.
-func (s *SiteverifyForm) GetRemoteip() string {
+func (s *SiteverifyForm) GetRemoteip() OptString {
 	return s.Remoteip
 }
 
@@ -81,68 +127,90 @@ func (s *SiteverifyForm) SetResponse(val string) {
 }
 
 // SetRemoteip sets the value of Remoteip.
-func (s *SiteverifyForm) SetRemoteip(val string) {
+func (s *SiteverifyForm) SetRemoteip(val OptString) {
 	s.Remoteip = val
 }
 
 // Ref: #/components/schemas/SiteverifyResponse
 type SiteverifyResponse struct {
-	Data OptSiteverifyResponseData `json:"data"`
-}
-
-// GetData returns the value of Data.
-func (s *SiteverifyResponse) GetData() OptSiteverifyResponseData {
-	return s.Data
-}
-
-// SetData sets the value of Data.
-func (s *SiteverifyResponse) SetData(val OptSiteverifyResponseData) {
-	s.Data = val
-}
-
-type SiteverifyResponseData struct {
-	Success         bool     `json:"success"`
-	ChallengeTs     string   `json:"challenge_ts"`
-	Hostname        string   `json:"hostname"`
-	ErrorMinusCodes []string `json:"error-codes"`
+	Success     bool      `json:"success"`
+	ChallengeTs OptString `json:"challenge_ts"`
+	Hostname    OptString `json:"hostname"`
+	// Score of reCAPTCHA v3 tokens, 1.0 is very likely a good interaction.
+	Score OptFloat64 `json:"score"`
+	// Action name of reCAPTCHA v3 tokens.
+	Action OptString `json:"action"`
+	// Package name of the Android app the challenge was solved in.
+	ApkPackageName  OptString `json:"apk_package_name"`
+	ErrorMinusCodes []string  `json:"error-codes"`
 }
 
 // GetSuccess returns the value of Success.
-func (s *SiteverifyResponseData) GetSuccess() bool {
+func (s *SiteverifyResponse) GetSuccess() bool {
 	return s.Success
 }
 
 // GetChallengeTs returns the value of ChallengeTs.
-func (s *SiteverifyResponseData) GetChallengeTs() string {
+func (s *SiteverifyResponse) GetChallengeTs() OptString {
 	return s.ChallengeTs
 }
 
 // GetHostname returns the value of Hostname.
-func (s *SiteverifyResponseData) GetHostname() string {
+func (s *SiteverifyResponse) GetHostname() OptString {
 	return s.Hostname
 }
 
+// GetScore returns the value of Score.
+func (s *SiteverifyResponse) GetScore() OptFloat64 {
+	return s.Score
+}
+
+// GetAction returns the value of Action.
+func (s *SiteverifyResponse) GetAction() OptString {
+	return s.Action
+}
+
+// GetApkPackageName returns the value of ApkPackageName.
+func (s *SiteverifyResponse) GetApkPackageName() OptString {
+	return s.ApkPackageName
+}
+
 // GetErrorMinusCodes returns the value of ErrorMinusCodes.
-func (s *SiteverifyResponseData) GetErrorMinusCodes() []string {
+func (s *SiteverifyResponse) GetErrorMinusCodes() []string {
 	return s.ErrorMinusCodes
 }
 
 // SetSuccess sets the value of Success.
-func (s *SiteverifyResponseData) SetSuccess(val bool) {
+func (s *SiteverifyResponse) SetSuccess(val bool) {
 	s.Success = val
 }
 
 // SetChallengeTs sets the value of ChallengeTs.
-func (s *SiteverifyResponseData) SetChallengeTs(val string) {
+func (s *SiteverifyResponse) SetChallengeTs(val OptString) {
 	s.ChallengeTs = val
 }
 
 // SetHostname sets the value of Hostname.
-func (s *SiteverifyResponseData) SetHostname(val string) {
+func (s *SiteverifyResponse) SetHostname(val OptString) {
 	s.Hostname = val
 }
 
+// SetScore sets the value of Score.
+func (s *SiteverifyResponse) SetScore(val OptFloat64) {
+	s.Score = val
+}
+
+// SetAction sets the value of Action.
+func (s *SiteverifyResponse) SetAction(val OptString) {
+	s.Action = val
+}
+
+// SetApkPackageName sets the value of ApkPackageName.
+func (s *SiteverifyResponse) SetApkPackageName(val OptString) {
+	s.ApkPackageName = val
+}
+
 // SetErrorMinusCodes sets the value of ErrorMinusCodes.
-func (s *SiteverifyResponseData) SetErrorMinusCodes(val []string) {
+func (s *SiteverifyResponse) SetErrorMinusCodes(val []string) {
 	s.ErrorMinusCodes = val
 }
@@ -0,0 +1,117 @@
+package google_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+	"github.com/theater-improrama/go-utils/captcha/google"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the contract tests")
+
+// outcome holds the fields of a verification pinned by the golden files
+type outcome struct {
+	Success        bool               `json:"success"`
+	Reasons        []recaptcha.Reason `json:"reasons,omitempty"`
+	Score          float64            `json:"score,omitempty"`
+	Action         string             `json:"action,omitempty"`
+	ChallengeTS    string             `json:"challenge_ts,omitempty"`
+	Hostname       string             `json:"hostname,omitempty"`
+	APKPackageName string             `json:"apk_package_name,omitempty"`
+	ErrorCodes     []string           `json:"error_codes,omitempty"`
+	ErrorKind      string             `json:"error_kind,omitempty"`
+}
+
+func newOutcome(res *recaptcha.Result, err error) outcome {
+	var cErr *recaptcha.Error
+	if errors.As(err, &cErr) {
+		return outcome{
+			Reasons:    cErr.Reasons,
+			ErrorCodes: cErr.ErrorCodes,
+			ErrorKind:  cErr.Kind.String(),
+		}
+	}
+
+	o := outcome{
+		Success:        res.Success,
+		Reasons:        res.Reasons,
+		Score:          res.Score,
+		Action:         res.Action,
+		Hostname:       res.Hostname,
+		APKPackageName: res.APKPackageName,
+		ErrorCodes:     res.ErrorCodes,
+	}
+	if !res.ChallengeTS.IsZero() {
+		o.ChallengeTS = res.ChallengeTS.Format(time.RFC3339)
+	}
+
+	return o
+}
+
+// Sample siteverify responses in testdata/siteverify/<name>.json, modelled on
+// the response format documented by Google, are served to the validator and
+// the outcome compared to testdata/siteverify/<name>.golden. v3_ responses are
+// verified by a v3 validator. Run go test with -update to rewrite the golden
+// files.
+var _ = Describe("Contract", func() {
+	responses, err := filepath.Glob(filepath.Join("testdata", "siteverify", "*.json"))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, p := range responses {
+		name := strings.TrimSuffix(filepath.Base(p), ".json")
+
+		It("should verify the sample response "+name, func() {
+			body, err := os.ReadFile(p)
+			Expect(err).ToNot(HaveOccurred())
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				_, _ = w.Write(body)
+			}))
+			DeferCleanup(srv.Close)
+
+			var v *google.RecaptchaValidator
+			if strings.HasPrefix(name, "v3_") {
+				v, err = google.NewV3Validator("secret", google.V3Config{
+					MinScore:  0.5,
+					Actions:   []string{"login"},
+					Hostnames: []string{"theater-improrama.de"},
+				}, google.WithBaseURL(srv.URL))
+			} else {
+				v, err = google.NewValidator("secret", google.WithBaseURL(srv.URL))
+			}
+			Expect(err).ToNot(HaveOccurred())
+
+			res, err := v.Verify(context.Background(), "token", nil)
+			if err != nil {
+				Expect(err).To(BeAssignableToTypeOf(&recaptcha.Error{}))
+			}
+
+			got, err := json.MarshalIndent(newOutcome(res, err), "", "  ")
+			Expect(err).ToNot(HaveOccurred())
+			got = append(got, '\n')
+
+			golden := strings.TrimSuffix(p, ".json") + ".golden"
+			if *update {
+				Expect(os.WriteFile(golden, got, 0o644)).To(Succeed())
+			}
+
+			want, err := os.ReadFile(golden)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(got)).To(Equal(string(want)))
+		})
+	}
+})
@@ -0,0 +1,13 @@
+package google_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGoogle(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Google Suite")
+}
@@ -0,0 +1,73 @@
+package google
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// TraceFunc is called before every siteverify request, including retries,
+// e.g. to start an OpenTelemetry span. The returned context is used for the
+// request and the returned function is called with the status code, 0 if no
+// response was received, and the error of the request once it completed.
+type TraceFunc func(ctx context.Context, attempt int) (context.Context, func(statusCode int, err error))
+
+type options struct {
+	baseURL    string
+	httpClient *http.Client
+	timeout    time.Duration
+	retries    int
+	backoff    time.Duration
+	trace      TraceFunc
+}
+
+func defaultOptions() options {
+	return options{
+		baseURL:    "https://www.google.com/recaptcha",
+		httpClient: http.DefaultClient,
+	}
+}
+
+type Option func(*options)
+
+// WithBaseURL sets the URL of the reCAPTCHA API, https://www.google.com/recaptcha
+// by default. Use https://www.recaptcha.net/recaptcha where google.com is
+// not reachable.
+func WithBaseURL(u string) Option {
+	return func(o *options) {
+		o.baseURL = u
+	}
+}
+
+// WithHTTPClient sets the HTTP client of siteverify calls
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
+// WithTimeout limits the duration of every siteverify request, no limit
+// besides the deadline of the context by default
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithRetry retries siteverify requests failing with network errors or 5xx
+// status codes up to retries times, waiting backoff before the first retry
+// and doubling the wait before every further retry. Requests are not retried
+// by default, negative retries disable retrying.
+func WithRetry(retries int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = max(retries, 0)
+		o.backoff = backoff
+	}
+}
+
+// WithTrace sets a hook called for every siteverify request
+func WithTrace(fn TraceFunc) Option {
+	return func(o *options) {
+		o.trace = fn
+	}
+}
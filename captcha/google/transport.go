@@ -0,0 +1,115 @@
+package google
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// transport performs siteverify requests with the timeout, retry and trace
+// options applied
+type transport struct {
+	o options
+}
+
+func (t *transport) Do(req *http.Request) (*http.Response, error) {
+	wait := t.o.backoff
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.do(req, attempt)
+		if attempt >= t.o.retries || !retryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		wait *= 2
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// do performs a single attempt of req
+func (t *transport) do(req *http.Request, attempt int) (*http.Response, error) {
+	ctx := req.Context()
+
+	end := func(int, error) {}
+	if t.o.trace != nil {
+		ctx, end = t.o.trace(ctx, attempt)
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if t.o.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.o.timeout)
+	}
+
+	resp, err := t.o.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		end(0, err)
+
+		return nil, err
+	}
+
+	end(resp.StatusCode, nil)
+
+	// the timeout covers reading the body, so the context is only canceled
+	// once the body is closed
+	resp.Body = &cancelBody{
+		ReadCloser: resp.Body,
+		cancel:     cancel,
+	}
+
+	return resp, nil
+}
+
+// retryable reports whether a request should be retried after resp or err
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
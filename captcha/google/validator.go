@@ -3,51 +3,175 @@ package google
 import (
 	"context"
 	"net"
+	"slices"
+	"time"
 
 	recaptcha "github.com/theater-improrama/go-utils/captcha"
 	"github.com/theater-improrama/go-utils/captcha/google/client"
 )
 
-type recaptchaValidator struct {
+// reasons maps Google's error codes of user errors to reasons
+var reasons = map[string]recaptcha.Reason{
+	"missing-input-response": recaptcha.ReasonMissingInput,
+	"invalid-input-response": recaptcha.ReasonInvalidInput,
+	"timeout-or-duplicate":   recaptcha.ReasonTimeoutOrDuplicate,
+}
+
+// configErrorCodes are Google's error codes caused by a misconfiguration
+var configErrorCodes = []string{
+	"missing-input-secret",
+	"invalid-input-secret",
+	"bad-request",
+}
+
+// V3Config configures the checks of reCAPTCHA v3 tokens
+type V3Config struct {
+	// MinScore is the minimum score of accepted tokens
+	MinScore float64
+	// Actions are the expected action names, any action is accepted if empty
+	Actions []string
+	// Hostnames are the allowed hostnames, any hostname is accepted if empty
+	Hostnames []string
+}
+
+type RecaptchaValidator struct {
 	c      *client.Client
 	secret string
+	v3     *V3Config
 }
 
-func NewValidator(secret string) (recaptcha.Validator, error) {
-	c, err := client.NewClient("https://www.google.com/recaptcha")
+func newValidator(secret string, v3 *V3Config, opts []Option) (*RecaptchaValidator, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c, err := client.NewClient(o.baseURL, client.WithClient(&transport{o: o}))
 	if err != nil {
 		return nil, err
 	}
 
-	return &recaptchaValidator{
+	return &RecaptchaValidator{
 		c:      c,
 		secret: secret,
+		v3:     v3,
 	}, nil
 }
 
-func (r *recaptchaValidator) Validate(
+// NewValidator returns a reCAPTCHA v2 validator
+func NewValidator(secret string, opts ...Option) (*RecaptchaValidator, error) {
+	return newValidator(secret, nil, opts)
+}
+
+// NewV3Validator returns a reCAPTCHA v3 validator, which additionally checks
+// the score, action and hostname of tokens
+func NewV3Validator(secret string, cfg V3Config, opts ...Option) (*RecaptchaValidator, error) {
+	return newValidator(secret, &cfg, opts)
+}
+
+// Verify verifies token and returns the result reported by Google, with
+// Success cleared and reasons appended for failed v3 checks. Errors are of
+// type *recaptcha.Error.
+func (r *RecaptchaValidator) Verify(
 	ctx context.Context,
 	token string,
 	clientIP net.IP,
-) (bool, error) {
-	resp, err := r.c.Siteverify(ctx, &client.SiteverifyForm{
+) (*recaptcha.Result, error) {
+	f := &client.SiteverifyForm{
 		Response: token,
 		Secret:   r.secret,
-		Remoteip: clientIP.String(),
-	})
+	}
+	if clientIP != nil {
+		f.Remoteip = client.NewOptString(clientIP.String())
+	}
+
+	resp, err := r.c.Siteverify(ctx, f)
 	if err != nil {
-		return false, err
+		return nil, &recaptcha.Error{
+			Kind: recaptcha.ErrorKindTransport,
+			Err:  err,
+		}
 	}
 
 	if err := resp.Validate(); err != nil {
-		return false, err
+		return nil, &recaptcha.Error{
+			Kind: recaptcha.ErrorKindTransport,
+			Err:  err,
+		}
+	}
+
+	for _, c := range resp.ErrorMinusCodes {
+		if slices.Contains(configErrorCodes, c) {
+			return nil, &recaptcha.Error{
+				Kind:       recaptcha.ErrorKindConfig,
+				ErrorCodes: resp.ErrorMinusCodes,
+			}
+		}
+	}
+
+	res := &recaptcha.Result{
+		Success:        resp.Success && len(resp.ErrorMinusCodes) == 0,
+		Score:          resp.Score.Or(0),
+		Action:         resp.Action.Or(""),
+		Hostname:       resp.Hostname.Or(""),
+		APKPackageName: resp.ApkPackageName.Or(""),
+		ErrorCodes:     resp.ErrorMinusCodes,
+	}
+
+	if ts, err := time.Parse(time.RFC3339, resp.ChallengeTs.Or("")); err == nil {
+		res.ChallengeTS = ts
 	}
 
-	if resp.Data.Set && len(resp.Data.Value.ErrorMinusCodes) > 0 {
-		return false, nil
+	for _, c := range resp.ErrorMinusCodes {
+		reason, ok := reasons[c]
+		if !ok {
+			reason = recaptcha.ReasonUnknown
+		}
+
+		res.Reasons = append(res.Reasons, reason)
+	}
+
+	if !res.Success && len(res.Reasons) == 0 {
+		res.Reasons = append(res.Reasons, recaptcha.ReasonUnknown)
+	}
+
+	if r.v3 != nil && res.Success {
+		r.check(res)
+	}
+
+	return res, nil
+}
+
+// check applies the v3 checks to res
+func (r *RecaptchaValidator) check(res *recaptcha.Result) {
+	if res.Score < r.v3.MinScore {
+		res.Reasons = append(res.Reasons, recaptcha.ReasonScoreTooLow)
+	}
+
+	if len(r.v3.Actions) > 0 && !slices.Contains(r.v3.Actions, res.Action) {
+		res.Reasons = append(res.Reasons, recaptcha.ReasonActionMismatch)
+	}
+
+	if len(r.v3.Hostnames) > 0 && !slices.Contains(r.v3.Hostnames, res.Hostname) {
+		res.Reasons = append(res.Reasons, recaptcha.ReasonHostnameMismatch)
+	}
+
+	res.Success = len(res.Reasons) == 0
+}
+
+func (r *RecaptchaValidator) Validate(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (bool, error) {
+	res, err := r.Verify(ctx, token, clientIP)
+	if err != nil {
+		return false, err
 	}
 
-	return true, nil
+	return res.Success, nil
 }
 
-var _ recaptcha.Validator = (*recaptchaValidator)(nil)
+var _ recaptcha.Verifier = (*RecaptchaValidator)(nil)
+
+var _ recaptcha.Validator = (*RecaptchaValidator)(nil)
@@ -0,0 +1,195 @@
+package google_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+	"github.com/theater-improrama/go-utils/captcha/google"
+)
+
+var _ = Describe("Validator", func() {
+	var (
+		srv      *httptest.Server
+		requests atomic.Int32
+		handler  http.HandlerFunc
+	)
+
+	BeforeEach(func() {
+		requests.Store(0)
+		handler = nil
+
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+
+			requests.Add(1)
+
+			Expect(r.URL.Path).To(Equal("/api/siteverify"))
+			Expect(r.ParseForm()).To(Succeed())
+			Expect(r.PostForm.Get("secret")).To(Equal("secret"))
+
+			handler(w, r)
+		}))
+		DeferCleanup(srv.Close)
+	})
+
+	respond := func(status int, body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}
+	}
+
+	It("should verify tokens against the configured base URL", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.PostForm.Get("response")).To(Equal("token"))
+			Expect(r.PostForm.Get("remoteip")).To(Equal("192.0.2.1"))
+
+			respond(http.StatusOK, `{"success":true,"challenge_ts":"2024-05-01T10:00:00Z","hostname":"example.com"}`)(w, r)
+		}
+
+		v, err := google.NewValidator("secret", google.WithBaseURL(srv.URL))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(v.Validate(context.Background(), "token", net.ParseIP("192.0.2.1"))).To(BeTrue())
+		Expect(requests.Load()).To(Equal(int32(1)))
+	})
+
+	It("should not send the client ip if unknown", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.PostForm).ToNot(HaveKey("remoteip"))
+
+			respond(http.StatusOK, `{"success":true}`)(w, r)
+		}
+
+		v, err := google.NewValidator("secret", google.WithBaseURL(srv.URL))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(v.Validate(context.Background(), "token", nil)).To(BeTrue())
+	})
+
+	It("should apply the v3 checks", func() {
+		handler = respond(http.StatusOK, `{"success":true,"score":0.3,"action":"signup","hostname":"evil.example"}`)
+
+		v, err := google.NewV3Validator("secret", google.V3Config{
+			MinScore:  0.5,
+			Actions:   []string{"login"},
+			Hostnames: []string{"example.com"},
+		}, google.WithBaseURL(srv.URL))
+		Expect(err).ToNot(HaveOccurred())
+
+		res, err := v.Verify(context.Background(), "token", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeFalse())
+		Expect(res.Score).To(Equal(0.3))
+		Expect(res.Action).To(Equal("signup"))
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{
+			recaptcha.ReasonScoreTooLow,
+			recaptcha.ReasonActionMismatch,
+			recaptcha.ReasonHostnameMismatch,
+		}))
+	})
+
+	It("should retry server errors with backoff", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			if requests.Load() < 3 {
+				respond(http.StatusServiceUnavailable, ``)(w, r)
+				return
+			}
+
+			respond(http.StatusOK, `{"success":true}`)(w, r)
+		}
+
+		v, err := google.NewValidator("secret",
+			google.WithBaseURL(srv.URL),
+			google.WithRetry(2, time.Millisecond),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(v.Validate(context.Background(), "token", nil)).To(BeTrue())
+		Expect(requests.Load()).To(Equal(int32(3)))
+	})
+
+	It("should report transport errors once retries are exhausted", func() {
+		handler = respond(http.StatusBadGateway, ``)
+
+		v, err := google.NewValidator("secret",
+			google.WithBaseURL(srv.URL),
+			google.WithRetry(1, time.Millisecond),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = v.Verify(context.Background(), "token", nil)
+
+		var cErr *recaptcha.Error
+		Expect(err).To(BeAssignableToTypeOf(cErr))
+		Expect(err.(*recaptcha.Error).Kind).To(Equal(recaptcha.ErrorKindTransport))
+		Expect(requests.Load()).To(Equal(int32(2)))
+	})
+
+	It("should not retry with negative retries", func() {
+		handler = respond(http.StatusBadGateway, ``)
+
+		v, err := google.NewValidator("secret",
+			google.WithBaseURL(srv.URL),
+			google.WithRetry(-1, time.Millisecond),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = v.Verify(context.Background(), "token", nil)
+		Expect(err).To(HaveOccurred())
+		Expect(requests.Load()).To(Equal(int32(1)))
+	})
+
+	It("should time out slow requests", func() {
+		handler = func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+
+		v, err := google.NewValidator("secret",
+			google.WithBaseURL(srv.URL),
+			google.WithTimeout(10*time.Millisecond),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = v.Verify(context.Background(), "token", nil)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should use the configured http client and trace requests", func() {
+		handler = respond(http.StatusOK, `{"success":true}`)
+
+		var (
+			attempts []int
+			statuses []int
+		)
+
+		v, err := google.NewValidator("secret",
+			google.WithBaseURL(srv.URL),
+			google.WithHTTPClient(srv.Client()),
+			google.WithTrace(func(ctx context.Context, attempt int) (context.Context, func(int, error)) {
+				attempts = append(attempts, attempt)
+
+				return ctx, func(status int, err error) {
+					Expect(err).ToNot(HaveOccurred())
+					statuses = append(statuses, status)
+				}
+			}),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(v.Validate(context.Background(), "token", nil)).To(BeTrue())
+		Expect(attempts).To(Equal([]int{0}))
+		Expect(statuses).To(Equal([]int{http.StatusOK}))
+	})
+})
@@ -0,0 +1,10 @@
+// Code generated by ogen, DO NOT EDIT.
+
+package client
+
+// OperationName is the ogen operation name
+type OperationName = string
+
+const (
+	SiteverifyOperation OperationName = "Siteverify"
+)
@@ -0,0 +1,261 @@
+// Code generated by ogen, DO NOT EDIT.
+
+package client
+
+// NewOptBool returns new OptBool with value set to v.
+func NewOptBool(v bool) OptBool {
+	return OptBool{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptBool is optional bool.
+type OptBool struct {
+	Value bool
+	Set   bool
+}
+
+// IsSet returns true if OptBool was set.
+func (o OptBool) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptBool) Reset() {
+	var v bool
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptBool) SetTo(v bool) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptBool) Get() (v bool, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptBool) Or(d bool) bool {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptFloat64 returns new OptFloat64 with value set to v.
+func NewOptFloat64(v float64) OptFloat64 {
+	return OptFloat64{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptFloat64 is optional float64.
+type OptFloat64 struct {
+	Value float64
+	Set   bool
+}
+
+// IsSet returns true if OptFloat64 was set.
+func (o OptFloat64) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptFloat64) Reset() {
+	var v float64
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptFloat64) SetTo(v float64) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptFloat64) Get() (v float64, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptFloat64) Or(d float64) float64 {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
+	return OptString{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
+func (o OptString) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptString) Reset() {
+	var v string
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptString) SetTo(v string) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptString) Get() (v string, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptString) Or(d string) string {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// Ref: #/components/schemas/SiteverifyForm
+type SiteverifyForm struct {
+	Secret   string    `json:"secret"`
+	Response string    `json:"response"`
+	Remoteip OptString `json:"remoteip"`
+	// Site key the token is expected to be issued for.
+	Sitekey OptString `json:"sitekey"`
+}
+
+// GetSecret returns the value of Secret.
+func (s *SiteverifyForm) GetSecret() string {
+	return s.Secret
+}
+
+// GetResponse returns the value of Response.
+func (s *SiteverifyForm) GetResponse() string {
+	return s.Response
+}
+
+// GetRemoteip returns the value of Remoteip.
+func (s *SiteverifyForm) GetRemoteip() OptString {
+	return s.Remoteip
+}
+
+// GetSitekey returns the value of Sitekey.
+func (s *SiteverifyForm) GetSitekey() OptString {
+	return s.Sitekey
+}
+
+// SetSecret sets the value of Secret.
+func (s *SiteverifyForm) SetSecret(val string) {
+	s.Secret = val
+}
+
+// SetResponse sets the value of Response.
+func (s *SiteverifyForm) SetResponse(val string) {
+	s.Response = val
+}
+
+// SetRemoteip sets the value of Remoteip.
+func (s *SiteverifyForm) SetRemoteip(val OptString) {
+	s.Remoteip = val
+}
+
+// SetSitekey sets the value of Sitekey.
+func (s *SiteverifyForm) SetSitekey(val OptString) {
+	s.Sitekey = val
+}
+
+// Ref: #/components/schemas/SiteverifyResponse
+type SiteverifyResponse struct {
+	Success     bool      `json:"success"`
+	ChallengeTs OptString `json:"challenge_ts"`
+	Hostname    OptString `json:"hostname"`
+	Credit      OptBool   `json:"credit"`
+	// Risk score of enterprise accounts, 1.0 is very likely a bot.
+	Score           OptFloat64 `json:"score"`
+	ErrorMinusCodes []string   `json:"error-codes"`
+}
+
+// GetSuccess returns the value of Success.
+func (s *SiteverifyResponse) GetSuccess() bool {
+	return s.Success
+}
+
+// GetChallengeTs returns the value of ChallengeTs.
+func (s *SiteverifyResponse) GetChallengeTs() OptString {
+	return s.ChallengeTs
+}
+
+// GetHostname returns the value of Hostname.
+func (s *SiteverifyResponse) GetHostname() OptString {
+	return s.Hostname
+}
+
+// GetCredit returns the value of Credit.
+func (s *SiteverifyResponse) GetCredit() OptBool {
+	return s.Credit
+}
+
+// GetScore returns the value of Score.
+func (s *SiteverifyResponse) GetScore() OptFloat64 {
+	return s.Score
+}
+
+// GetErrorMinusCodes returns the value of ErrorMinusCodes.
+func (s *SiteverifyResponse) GetErrorMinusCodes() []string {
+	return s.ErrorMinusCodes
+}
+
+// SetSuccess sets the value of Success.
+func (s *SiteverifyResponse) SetSuccess(val bool) {
+	s.Success = val
+}
+
+// SetChallengeTs sets the value of ChallengeTs.
+func (s *SiteverifyResponse) SetChallengeTs(val OptString) {
+	s.ChallengeTs = val
+}
+
+// SetHostname sets the value of Hostname.
+func (s *SiteverifyResponse) SetHostname(val OptString) {
+	s.Hostname = val
+}
+
+// SetCredit sets the value of Credit.
+func (s *SiteverifyResponse) SetCredit(val OptBool) {
+	s.Credit = val
+}
+
+// SetScore sets the value of Score.
+func (s *SiteverifyResponse) SetScore(val OptFloat64) {
+	s.Score = val
+}
+
+// SetErrorMinusCodes sets the value of ErrorMinusCodes.
+func (s *SiteverifyResponse) SetErrorMinusCodes(val []string) {
+	s.ErrorMinusCodes = val
+}
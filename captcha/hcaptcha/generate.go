@@ -0,0 +1,2 @@
+//go:generate go run github.com/ogen-go/ogen/cmd/ogen@latest --package client --config ogen.yaml --target ./client --clean openapi.yaml
+package hcaptcha
@@ -0,0 +1,13 @@
+package hcaptcha_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHcaptcha(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Hcaptcha Suite")
+}
@@ -0,0 +1,184 @@
+package hcaptcha
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+	"github.com/theater-improrama/go-utils/captcha/hcaptcha/client"
+)
+
+// reasons maps hCaptcha's error codes of user errors to reasons
+var reasons = map[string]recaptcha.Reason{
+	"missing-input-response":           recaptcha.ReasonMissingInput,
+	"invalid-input-response":           recaptcha.ReasonInvalidInput,
+	"missing-remoteip":                 recaptcha.ReasonInvalidInput,
+	"invalid-remoteip":                 recaptcha.ReasonInvalidInput,
+	"expired-input-response":           recaptcha.ReasonTimeoutOrDuplicate,
+	"already-seen-response":            recaptcha.ReasonTimeoutOrDuplicate,
+	"invalid-or-already-seen-response": recaptcha.ReasonTimeoutOrDuplicate,
+}
+
+// configErrorCodes are hCaptcha's error codes caused by a misconfiguration
+var configErrorCodes = []string{
+	"missing-input-secret",
+	"invalid-input-secret",
+	"sitekey-secret-mismatch",
+	"not-using-dummy-passcode",
+	"bad-request",
+}
+
+// Config configures the checks of hCaptcha tokens
+type Config struct {
+	// SiteKey is the site key tokens must be issued for, not checked if empty
+	SiteKey string
+	// Hostnames are the allowed hostnames, any hostname is accepted if empty
+	Hostnames []string
+}
+
+type options struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type Option func(*options)
+
+// WithBaseURL sets the URL of the hCaptcha API, https://api.hcaptcha.com by default
+func WithBaseURL(u string) Option {
+	return func(o *options) {
+		o.baseURL = u
+	}
+}
+
+// WithHTTPClient sets the HTTP client of siteverify calls
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
+type HcaptchaValidator struct {
+	c      *client.Client
+	secret string
+	cfg    Config
+}
+
+func NewValidator(secret string, cfg Config, opts ...Option) (*HcaptchaValidator, error) {
+	o := options{
+		baseURL:    "https://api.hcaptcha.com",
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c, err := client.NewClient(o.baseURL, client.WithClient(o.httpClient))
+	if err != nil {
+		return nil, err
+	}
+
+	return &HcaptchaValidator{
+		c:      c,
+		secret: secret,
+		cfg:    cfg,
+	}, nil
+}
+
+// Verify verifies token and returns the result reported by hCaptcha, with
+// Success cleared and reasons appended for failed hostname checks. Errors
+// are of type *recaptcha.Error.
+func (h *HcaptchaValidator) Verify(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (*recaptcha.Result, error) {
+	f := &client.SiteverifyForm{
+		Response: token,
+		Secret:   h.secret,
+	}
+	if clientIP != nil {
+		f.Remoteip = client.NewOptString(clientIP.String())
+	}
+	if h.cfg.SiteKey != "" {
+		f.Sitekey = client.NewOptString(h.cfg.SiteKey)
+	}
+
+	resp, err := h.c.Siteverify(ctx, f)
+	if err != nil {
+		return nil, &recaptcha.Error{
+			Kind: recaptcha.ErrorKindTransport,
+			Err:  err,
+		}
+	}
+
+	if err := resp.Validate(); err != nil {
+		return nil, &recaptcha.Error{
+			Kind: recaptcha.ErrorKindTransport,
+			Err:  err,
+		}
+	}
+
+	for _, c := range resp.ErrorMinusCodes {
+		if slices.Contains(configErrorCodes, c) {
+			return nil, &recaptcha.Error{
+				Kind:       recaptcha.ErrorKindConfig,
+				ErrorCodes: resp.ErrorMinusCodes,
+			}
+		}
+	}
+
+	res := &recaptcha.Result{
+		Success:    resp.Success && len(resp.ErrorMinusCodes) == 0,
+		Hostname:   resp.Hostname.Or(""),
+		ErrorCodes: resp.ErrorMinusCodes,
+	}
+
+	// hCaptcha reports the risk of a bot, results the likelihood of a human
+	if score, ok := resp.Score.Get(); ok {
+		res.Score = 1 - score
+	}
+
+	if ts, err := time.Parse(time.RFC3339, resp.ChallengeTs.Or("")); err == nil {
+		res.ChallengeTS = ts
+	}
+
+	for _, c := range resp.ErrorMinusCodes {
+		reason, ok := reasons[c]
+		if !ok {
+			reason = recaptcha.ReasonUnknown
+		}
+
+		res.Reasons = append(res.Reasons, reason)
+	}
+
+	if !res.Success && len(res.Reasons) == 0 {
+		res.Reasons = append(res.Reasons, recaptcha.ReasonUnknown)
+	}
+
+	if res.Success && len(h.cfg.Hostnames) > 0 && !slices.Contains(h.cfg.Hostnames, res.Hostname) {
+		res.Success = false
+		res.Reasons = append(res.Reasons, recaptcha.ReasonHostnameMismatch)
+	}
+
+	return res, nil
+}
+
+func (h *HcaptchaValidator) Validate(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (bool, error) {
+	res, err := h.Verify(ctx, token, clientIP)
+	if err != nil {
+		return false, err
+	}
+
+	return res.Success, nil
+}
+
+var _ recaptcha.Verifier = (*HcaptchaValidator)(nil)
+
+var _ recaptcha.Validator = (*HcaptchaValidator)(nil)
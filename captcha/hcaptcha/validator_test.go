@@ -0,0 +1,111 @@
+package hcaptcha_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+	"github.com/theater-improrama/go-utils/captcha/hcaptcha"
+)
+
+var _ = Describe("Validator", func() {
+	var (
+		srv  *httptest.Server
+		form url.Values
+		body string
+	)
+
+	BeforeEach(func() {
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/siteverify"))
+			Expect(r.ParseForm()).To(Succeed())
+			form = r.PostForm
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		}))
+		DeferCleanup(srv.Close)
+	})
+
+	newValidator := func(cfg hcaptcha.Config) *hcaptcha.HcaptchaValidator {
+		v, err := hcaptcha.NewValidator("secret", cfg, hcaptcha.WithBaseURL(srv.URL))
+		Expect(err).ToNot(HaveOccurred())
+
+		return v
+	}
+
+	It("should verify tokens and send the site key", func() {
+		body = `{"success":true,"challenge_ts":"2024-05-01T10:00:00Z","hostname":"example.com","credit":false}`
+
+		res, err := newValidator(hcaptcha.Config{SiteKey: "sitekey"}).Verify(context.Background(), "token", net.ParseIP("192.0.2.1"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeTrue())
+		Expect(res.Hostname).To(Equal("example.com"))
+		Expect(res.ChallengeTS.Unix()).To(Equal(int64(1714557600)))
+
+		Expect(form.Get("secret")).To(Equal("secret"))
+		Expect(form.Get("response")).To(Equal("token"))
+		Expect(form.Get("remoteip")).To(Equal("192.0.2.1"))
+		Expect(form.Get("sitekey")).To(Equal("sitekey"))
+	})
+
+	It("should not send the client ip and site key if unset", func() {
+		body = `{"success":true}`
+
+		Expect(newValidator(hcaptcha.Config{}).Validate(context.Background(), "token", nil)).To(BeTrue())
+		Expect(form).ToNot(HaveKey("remoteip"))
+		Expect(form).ToNot(HaveKey("sitekey"))
+	})
+
+	It("should map risk scores to the likelihood of a human", func() {
+		body = `{"success":true,"score":0.25}`
+
+		res, err := newValidator(hcaptcha.Config{}).Verify(context.Background(), "token", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Score).To(Equal(0.75))
+	})
+
+	It("should map error codes to reasons", func() {
+		body = `{"success":false,"error-codes":["already-seen-response"]}`
+
+		res, err := newValidator(hcaptcha.Config{}).Verify(context.Background(), "token", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeFalse())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonTimeoutOrDuplicate}))
+	})
+
+	It("should report site key mismatches as config errors", func() {
+		body = `{"success":false,"error-codes":["sitekey-secret-mismatch"]}`
+
+		_, err := newValidator(hcaptcha.Config{SiteKey: "other"}).Verify(context.Background(), "token", nil)
+
+		var cErr *recaptcha.Error
+		Expect(err).To(BeAssignableToTypeOf(cErr))
+		Expect(err.(*recaptcha.Error).Kind).To(Equal(recaptcha.ErrorKindConfig))
+	})
+
+	It("should reject tokens of hostnames not allowed", func() {
+		body = `{"success":true,"hostname":"evil.example"}`
+
+		res, err := newValidator(hcaptcha.Config{Hostnames: []string{"example.com"}}).Verify(context.Background(), "token", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeFalse())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonHostnameMismatch}))
+	})
+
+	It("should report transport errors", func() {
+		srv.Close()
+
+		_, err := newValidator(hcaptcha.Config{}).Verify(context.Background(), "token", nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*recaptcha.Error).Kind).To(Equal(recaptcha.ErrorKindTransport))
+	})
+})
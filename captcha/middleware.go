@@ -0,0 +1,196 @@
+package recaptcha
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// DefaultTokenHeader is the header the middleware reads tokens from by default
+const DefaultTokenHeader = "X-Captcha-Token"
+
+// DefaultTokenFields are the form fields the widgets of the supported
+// providers submit tokens in
+var DefaultTokenFields = []string{
+	"g-recaptcha-response",
+	"h-captcha-response",
+	"cf-turnstile-response",
+}
+
+// MiddlewareConfig configures a Middleware
+type MiddlewareConfig struct {
+	// Token extracts the token from a request. By default the token is read
+	// from the DefaultTokenHeader header or, if missing, from the first
+	// non-empty form field of DefaultTokenFields.
+	Token func(r *http.Request) string
+	// TrustedProxies are the CIDRs of the reverse proxies whose
+	// X-Forwarded-For header is trusted to resolve the client IP. Without
+	// trusted proxies the client IP is the remote address of the request.
+	TrustedProxies []string
+	// Reject writes the response to requests which did not pass the captcha.
+	// err is set for configuration and transport errors, res otherwise. By
+	// default 403 is returned for rejected tokens and 503 for errors.
+	Reject func(w http.ResponseWriter, r *http.Request, res *Result, err error)
+}
+
+// Middleware enforces captchas on HTTP handlers
+type Middleware struct {
+	v       Verifier
+	token   func(r *http.Request) string
+	proxies []netip.Prefix
+	reject  func(w http.ResponseWriter, r *http.Request, res *Result, err error)
+}
+
+func NewMiddleware(v Verifier, cfg MiddlewareConfig) (*Middleware, error) {
+	m := &Middleware{
+		v:      v,
+		token:  cfg.Token,
+		reject: cfg.Reject,
+	}
+
+	if m.token == nil {
+		m.token = defaultToken
+	}
+
+	if m.reject == nil {
+		m.reject = defaultReject
+	}
+
+	for _, p := range cfg.TrustedProxies {
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, err
+		}
+
+		m.proxies = append(m.proxies, prefix.Masked())
+	}
+
+	return m, nil
+}
+
+func defaultToken(r *http.Request) string {
+	if t := r.Header.Get(DefaultTokenHeader); t != "" {
+		return t
+	}
+
+	for _, f := range DefaultTokenFields {
+		if t := r.FormValue(f); t != "" {
+			return t
+		}
+	}
+
+	return ""
+}
+
+func defaultReject(w http.ResponseWriter, _ *http.Request, _ *Result, err error) {
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	http.Error(w, ErrInvalidCaptcha.Error(), http.StatusForbidden)
+}
+
+// Protect returns a handler passing requests with a valid token to next. If
+// action is not empty, tokens must have been created for action, which is
+// only reported by score based captchas like reCAPTCHA v3 and Turnstile, and
+// is not checked for results decided by a failure policy. The result is
+// available to next by ResultFromContext.
+func (m *Middleware) Protect(action string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, err := m.v.Verify(r.Context(), m.token(r), m.ClientIP(r))
+		if err != nil {
+			m.reject(w, r, nil, err)
+			return
+		}
+
+		// results decided by a failure policy carry no action
+		if res.Success && !res.Fallback && action != "" && res.Action != action {
+			mismatch := *res
+			mismatch.Success = false
+			mismatch.Reasons = append(slices.Clone(res.Reasons), ReasonActionMismatch)
+			res = &mismatch
+		}
+
+		if !res.Success {
+			m.reject(w, r, res, nil)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithResult(r.Context(), res)))
+	})
+}
+
+// ClientIP resolves the IP of the client of r. The X-Forwarded-For header is
+// only evaluated if the request was received from a trusted proxy, and is
+// read from right to left, skipping trusted proxies, as all entries left of
+// the last untrusted address may be forged. nil is returned if the client
+// cannot be resolved, e.g. behind a malformed hop.
+func (m *Middleware) ClientIP(r *http.Request) net.IP {
+	ip, err := remoteIP(r.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+
+	if !m.trusted(ip) {
+		return net.IP(ip.AsSlice())
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			return nil
+		}
+
+		ip = hop.Unmap()
+		if !m.trusted(ip) {
+			break
+		}
+	}
+
+	return net.IP(ip.AsSlice())
+}
+
+func remoteIP(addr string) (netip.Addr, error) {
+	ap, err := netip.ParseAddrPort(addr)
+	if err == nil {
+		return ap.Addr().Unmap(), nil
+	}
+
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	return ip.Unmap(), nil
+}
+
+func (m *Middleware) trusted(ip netip.Addr) bool {
+	for _, p := range m.proxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type resultCtxKey struct{}
+
+// ContextWithResult returns a context carrying the verification result res
+func ContextWithResult(ctx context.Context, res *Result) context.Context {
+	return context.WithValue(ctx, resultCtxKey{}, res)
+}
+
+// ResultFromContext returns the verification result carried by ctx, e.g. to
+// log the score of the token in a handler protected by a Middleware
+func ResultFromContext(ctx context.Context) (*Result, bool) {
+	res, ok := ctx.Value(resultCtxKey{}).(*Result)
+
+	return res, ok
+}
@@ -0,0 +1,159 @@
+package recaptcha_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+)
+
+var _ = Describe("Middleware", func() {
+	var (
+		token    string
+		clientIP net.IP
+		verifier recaptcha.Verifier
+	)
+
+	BeforeEach(func() {
+		verifier = verifierFunc(func(_ context.Context, t string, ip net.IP) (*recaptcha.Result, error) {
+			token, clientIP = t, ip
+
+			switch t {
+			case "error":
+				return nil, &recaptcha.Error{Kind: recaptcha.ErrorKindTransport}
+			case "login", "signup":
+				return &recaptcha.Result{Success: true, Action: t, Score: 0.9}, nil
+			default:
+				return &recaptcha.Result{Reasons: []recaptcha.Reason{recaptcha.ReasonInvalidInput}}, nil
+			}
+		})
+	})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, ok := recaptcha.ResultFromContext(r.Context())
+		Expect(ok).To(BeTrue())
+		Expect(res.Success).To(BeTrue())
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	serve := func(m *recaptcha.Middleware, action string, r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		m.Protect(action, next).ServeHTTP(w, r)
+
+		return w
+	}
+
+	newMiddleware := func(cfg recaptcha.MiddlewareConfig) *recaptcha.Middleware {
+		m, err := recaptcha.NewMiddleware(verifier, cfg)
+		Expect(err).ToNot(HaveOccurred())
+
+		return m
+	}
+
+	It("should pass requests with valid tokens from the header or form", func() {
+		m := newMiddleware(recaptcha.MiddlewareConfig{})
+
+		r := httptest.NewRequest(http.MethodPost, "/login", nil)
+		r.Header.Set(recaptcha.DefaultTokenHeader, "login")
+		Expect(serve(m, "login", r).Code).To(Equal(http.StatusNoContent))
+		Expect(clientIP.String()).To(Equal("192.0.2.1"))
+
+		r = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(url.Values{
+			"h-captcha-response": {"login"},
+		}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		Expect(serve(m, "", r).Code).To(Equal(http.StatusNoContent))
+		Expect(token).To(Equal("login"))
+	})
+
+	It("should reject invalid tokens, tokens of other actions and errors", func() {
+		m := newMiddleware(recaptcha.MiddlewareConfig{})
+
+		for tok, code := range map[string]int{
+			"invalid": http.StatusForbidden,
+			"signup":  http.StatusForbidden,
+			"error":   http.StatusServiceUnavailable,
+		} {
+			r := httptest.NewRequest(http.MethodPost, "/login", nil)
+			r.Header.Set(recaptcha.DefaultTokenHeader, tok)
+			Expect(serve(m, "login", r).Code).To(Equal(code), tok)
+		}
+	})
+
+	It("should pass requests accepted by a fail-open fallback", func() {
+		verifier = recaptcha.NewFallbackVerifier(verifier, recaptcha.FallbackConfig{Policy: recaptcha.FailOpen})
+		m := newMiddleware(recaptcha.MiddlewareConfig{})
+
+		r := httptest.NewRequest(http.MethodPost, "/login", nil)
+		r.Header.Set(recaptcha.DefaultTokenHeader, "error")
+		Expect(serve(m, "login", r).Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("should use custom token extraction and rejection", func() {
+		var rejected *recaptcha.Result
+
+		m := newMiddleware(recaptcha.MiddlewareConfig{
+			Token: func(r *http.Request) string {
+				return r.URL.Query().Get("captcha")
+			},
+			Reject: func(w http.ResponseWriter, _ *http.Request, res *recaptcha.Result, _ error) {
+				rejected = res
+				w.WriteHeader(http.StatusTeapot)
+			},
+		})
+
+		r := httptest.NewRequest(http.MethodPost, "/login?captcha=signup", nil)
+		Expect(serve(m, "login", r).Code).To(Equal(http.StatusTeapot))
+		Expect(rejected.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonActionMismatch}))
+		Expect(rejected.Score).To(Equal(0.9))
+	})
+
+	Describe("ClientIP", func() {
+		m := func() *recaptcha.Middleware {
+			m, err := recaptcha.NewMiddleware(recaptcha.AcceptAll(), recaptcha.MiddlewareConfig{
+				TrustedProxies: []string{"10.0.0.0/8", "2001:db8::/32"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			return m
+		}
+
+		request := func(remoteAddr string, xff ...string) *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = remoteAddr
+			for _, v := range xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+
+			return r
+		}
+
+		It("should ignore X-Forwarded-For of untrusted peers", func() {
+			Expect(m().ClientIP(request("198.51.100.7:4711", "203.0.113.9")).String()).To(Equal("198.51.100.7"))
+		})
+
+		It("should resolve the first untrusted hop from the right", func() {
+			Expect(m().ClientIP(request("10.0.0.1:4711", "203.0.113.66, 203.0.113.9, 10.1.2.3")).String()).To(Equal("203.0.113.9"))
+			Expect(m().ClientIP(request("[2001:db8::1]:4711", "203.0.113.66", "203.0.113.9")).String()).To(Equal("203.0.113.9"))
+		})
+
+		It("should not resolve clients behind malformed hops", func() {
+			Expect(m().ClientIP(request("10.0.0.1:4711", "203.0.113.9, garbage, 10.1.2.3"))).To(BeNil())
+			Expect(m().ClientIP(request("10.0.0.1:4711", "garbage"))).To(BeNil())
+		})
+
+		It("should reject invalid CIDRs", func() {
+			_, err := recaptcha.NewMiddleware(recaptcha.AcceptAll(), recaptcha.MiddlewareConfig{
+				TrustedProxies: []string{"10.0.0.0"},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,217 @@
+package recaptcha
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultReplayTTL is the lifetime of the longest lived tokens of the
+// supported providers
+const DefaultReplayTTL = 5 * time.Minute
+
+// ReplayEntry is the outcome of the first verification of a token
+type ReplayEntry struct {
+	// ReuseKey is the reuse key of the first verification, empty if none
+	ReuseKey string
+	Result   Result
+}
+
+// ReplayStore records verified tokens by the hash of the token
+type ReplayStore interface {
+	// Get returns the entry of key, nil if key is not recorded or expired
+	Get(ctx context.Context, key string) (*ReplayEntry, error)
+	// Add records e for key for ttl unless key is already recorded and reports
+	// whether it was added. Implementations must perform the check and the
+	// update atomically.
+	Add(ctx context.Context, key string, e ReplayEntry, ttl time.Duration) (bool, error)
+}
+
+// MemoryReplayStore is a ReplayStore keeping entries in memory, suited for
+// single instance deployments and tests
+type MemoryReplayStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryReplayEntry
+	swept   time.Time
+}
+
+type memoryReplayEntry struct {
+	e       ReplayEntry
+	expires time.Time
+}
+
+func NewMemoryReplayStore() *MemoryReplayStore {
+	return &MemoryReplayStore{
+		entries: make(map[string]memoryReplayEntry),
+		swept:   time.Now(),
+	}
+}
+
+func (s *MemoryReplayStore) Get(_ context.Context, key string) (*ReplayEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !time.Now().Before(e.expires) {
+		return nil, nil
+	}
+
+	return &e.e, nil
+}
+
+func (s *MemoryReplayStore) Add(_ context.Context, key string, e ReplayEntry, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if o, ok := s.entries[key]; ok && now.Before(o.expires) {
+		return false, nil
+	}
+
+	s.entries[key] = memoryReplayEntry{
+		e:       e,
+		expires: now.Add(ttl),
+	}
+
+	return true, nil
+}
+
+// sweep removes expired entries at most once a minute
+func (s *MemoryReplayStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+
+	s.swept = now
+}
+
+var _ ReplayStore = (*MemoryReplayStore)(nil)
+
+type reuseKeyCtxKey struct{}
+
+// WithReuseKey returns a context allowing a ReplayVerifier to reuse the
+// result of a token verified before with the same reuse key, e.g. the ID of
+// a multi-step flow verifying the token on several endpoints
+func WithReuseKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, reuseKeyCtxKey{}, key)
+}
+
+// ReplayVerifier lets every token be verified only once. Tokens verified
+// again are rejected with ReasonTimeoutOrDuplicate without calling the
+// wrapped verifier, unless both verifications share the same reuse key, in
+// which case the result of the first verification is returned. Wrap
+// Validators with FromValidator.
+type ReplayVerifier struct {
+	v   Verifier
+	s   ReplayStore
+	ttl time.Duration
+}
+
+// NewReplayVerifier returns a verifier recording tokens in s for ttl, which
+// should be at least the lifetime of the tokens
+func NewReplayVerifier(v Verifier, s ReplayStore, ttl time.Duration) *ReplayVerifier {
+	return &ReplayVerifier{
+		v:   v,
+		s:   s,
+		ttl: ttl,
+	}
+}
+
+// replayKey returns the store key of token, so tokens are not stored in clear
+func replayKey(token string) string {
+	h := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(h[:])
+}
+
+// Verify verifies token unless it was verified before. Looking up, verifying
+// and recording a token is not atomic: concurrent verifications of the same
+// token may all call the wrapped verifier, but only the first one recorded is
+// accepted. Results decided by a failure policy are not recorded, so tokens
+// accepted while the provider was unavailable are verified again.
+func (r *ReplayVerifier) Verify(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (*Result, error) {
+	if token == "" {
+		return r.v.Verify(ctx, token, clientIP)
+	}
+
+	key := replayKey(token)
+	reuseKey, _ := ctx.Value(reuseKeyCtxKey{}).(string)
+
+	e, err := r.s.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if e != nil {
+		return replayed(e, reuseKey), nil
+	}
+
+	res, err := r.v.Verify(ctx, token, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Fallback {
+		return res, nil
+	}
+
+	ok, err := r.s.Add(ctx, key, ReplayEntry{
+		ReuseKey: reuseKey,
+		Result:   *res.clone(),
+	}, r.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	// a concurrent verification of the same token won
+	if !ok {
+		e, err := r.s.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if e != nil {
+			return replayed(e, reuseKey), nil
+		}
+	}
+
+	return res, nil
+}
+
+// replayed returns the result of verifying a token recorded as e again
+func replayed(e *ReplayEntry, reuseKey string) *Result {
+	if reuseKey != "" && reuseKey == e.ReuseKey {
+		return e.Result.clone()
+	}
+
+	return &Result{
+		Reasons: []Reason{ReasonTimeoutOrDuplicate},
+	}
+}
+
+func (r *ReplayVerifier) Validate(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (bool, error) {
+	return AsValidator(r).Validate(ctx, token, clientIP)
+}
+
+var _ Verifier = (*ReplayVerifier)(nil)
+
+var _ Validator = (*ReplayVerifier)(nil)
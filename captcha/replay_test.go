@@ -0,0 +1,109 @@
+package recaptcha_test
+
+import (
+	"context"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+)
+
+var _ = Describe("ReplayVerifier", func() {
+	ctx := context.Background()
+
+	var (
+		calls int
+		r     *recaptcha.ReplayVerifier
+	)
+
+	BeforeEach(func() {
+		calls = 0
+
+		r = recaptcha.NewReplayVerifier(verifierFunc(func(_ context.Context, token string, _ net.IP) (*recaptcha.Result, error) {
+			calls++
+
+			switch token {
+			case "invalid":
+				return &recaptcha.Result{Reasons: []recaptcha.Reason{recaptcha.ReasonInvalidInput}}, nil
+			case "unavailable":
+				return &recaptcha.Result{Success: true, Fallback: true}, nil
+			}
+
+			return &recaptcha.Result{Success: true, Hostname: "example.com"}, nil
+		}), recaptcha.NewMemoryReplayStore(), recaptcha.DefaultReplayTTL)
+	})
+
+	It("should reject tokens verified before", func() {
+		Expect(r.Validate(ctx, "token", nil)).To(BeTrue())
+
+		res, err := r.Verify(ctx, "token", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeFalse())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonTimeoutOrDuplicate}))
+		Expect(calls).To(Equal(1))
+
+		Expect(r.Validate(ctx, "other", nil)).To(BeTrue())
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should reuse results within the same flow only", func() {
+		flow := recaptcha.WithReuseKey(ctx, "flow-1")
+
+		Expect(r.Validate(flow, "token", nil)).To(BeTrue())
+
+		res, err := r.Verify(flow, "token", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeTrue())
+		Expect(res.Hostname).To(Equal("example.com"))
+
+		Expect(r.Validate(recaptcha.WithReuseKey(ctx, "flow-2"), "token", nil)).To(BeFalse())
+		Expect(r.Validate(ctx, "token", nil)).To(BeFalse())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should reuse rejections within the same flow", func() {
+		flow := recaptcha.WithReuseKey(ctx, "flow-1")
+
+		Expect(r.Validate(flow, "invalid", nil)).To(BeFalse())
+		Expect(r.Validate(flow, "invalid", nil)).To(BeFalse())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should not share the reasons of recorded results", func() {
+		flow := recaptcha.WithReuseKey(ctx, "flow-1")
+
+		res, err := r.Verify(flow, "invalid", nil)
+		Expect(err).ToNot(HaveOccurred())
+		res.Reasons[0] = recaptcha.ReasonUnknown
+
+		res, err = r.Verify(flow, "invalid", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonInvalidInput}))
+		res.Reasons[0] = recaptcha.ReasonUnknown
+
+		res, err = r.Verify(flow, "invalid", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonInvalidInput}))
+	})
+
+	It("should not record results decided by a failure policy", func() {
+		Expect(r.Validate(ctx, "unavailable", nil)).To(BeTrue())
+		Expect(r.Validate(ctx, "unavailable", nil)).To(BeTrue())
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should forget tokens after the ttl", func() {
+		s := recaptcha.NewMemoryReplayStore()
+
+		Expect(s.Add(ctx, "key", recaptcha.ReplayEntry{}, 10*time.Millisecond)).To(BeTrue())
+		Expect(s.Add(ctx, "key", recaptcha.ReplayEntry{}, 10*time.Millisecond)).To(BeFalse())
+		Expect(s.Get(ctx, "key")).ToNot(BeNil())
+
+		Eventually(func() (*recaptcha.ReplayEntry, error) {
+			return s.Get(ctx, "key")
+		}).Should(BeNil())
+		Expect(s.Add(ctx, "key", recaptcha.ReplayEntry{}, 10*time.Millisecond)).To(BeTrue())
+	})
+})
@@ -0,0 +1,151 @@
+package recaptcha
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"time"
+)
+
+// Reason is a provider-neutral reason for rejecting a captcha token
+type Reason string
+
+const (
+	// ReasonMissingInput is reported for requests without token
+	ReasonMissingInput Reason = "missing-input"
+	// ReasonInvalidInput is reported for malformed or forged tokens
+	ReasonInvalidInput Reason = "invalid-input"
+	// ReasonTimeoutOrDuplicate is reported for expired tokens and tokens
+	// which were already verified
+	ReasonTimeoutOrDuplicate Reason = "timeout-or-duplicate"
+	// ReasonScoreTooLow is reported for tokens scored below the minimum score
+	ReasonScoreTooLow Reason = "score-too-low"
+	// ReasonActionMismatch is reported for tokens of unexpected actions
+	ReasonActionMismatch Reason = "action-mismatch"
+	// ReasonHostnameMismatch is reported for tokens solved on hostnames not allowed
+	ReasonHostnameMismatch Reason = "hostname-mismatch"
+	// ReasonUnavailable is reported for tokens rejected without verification
+	// because the provider is unavailable
+	ReasonUnavailable Reason = "unavailable"
+	// ReasonUnknown is reported for rejections without known error code
+	ReasonUnknown Reason = "unknown"
+)
+
+// Result is the outcome of verifying a captcha token
+type Result struct {
+	// Success reports whether the token is valid and passed all configured checks
+	Success bool
+	// Reasons are the reasons the token was rejected for, empty on success
+	Reasons []Reason
+	// Score is the likelihood of a human interaction from 0.0 to 1.0, only set
+	// by score based captchas like reCAPTCHA v3
+	Score float64
+	// Action is the action name the token was created for, only set by score
+	// based captchas like reCAPTCHA v3
+	Action string
+	// ChallengeTS is the time the challenge was solved
+	ChallengeTS time.Time
+	// Hostname is the hostname of the site the challenge was solved on
+	Hostname string
+	// APKPackageName is the package name of the Android app the challenge was
+	// solved in, only set by reCAPTCHA for Android instead of Hostname
+	APKPackageName string
+	// CData is the customer data bound to the token on the client, only set
+	// by Turnstile
+	CData string
+	// ErrorCodes are the raw error codes reported by the provider
+	ErrorCodes []string
+	// Fallback reports whether the result was decided by a failure policy
+	// instead of the provider
+	Fallback bool
+}
+
+// clone returns a copy of r not sharing its slices
+func (r *Result) clone() *Result {
+	c := *r
+	c.Reasons = slices.Clone(r.Reasons)
+	c.ErrorCodes = slices.Clone(r.ErrorCodes)
+
+	return &c
+}
+
+// Err returns a user error with the reasons of a rejected token, nil if the
+// token was accepted
+func (r *Result) Err() error {
+	if r.Success {
+		return nil
+	}
+
+	return &Error{
+		Kind:    ErrorKindUser,
+		Reasons: r.Reasons,
+	}
+}
+
+// Verifier verifies captcha tokens. Rejected tokens are reported by the
+// result, errors are only returned for configuration and transport failures.
+type Verifier interface {
+	Verify(
+		ctx context.Context,
+		token string,
+		clientIP net.IP,
+	) (*Result, error)
+}
+
+type verifierValidator struct {
+	v Verifier
+}
+
+// AsValidator adapts v to the boolean Validator interface
+func AsValidator(v Verifier) Validator {
+	return &verifierValidator{
+		v: v,
+	}
+}
+
+func (v *verifierValidator) Validate(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (bool, error) {
+	res, err := v.v.Verify(ctx, token, clientIP)
+	if err != nil {
+		return false, err
+	}
+
+	return res.Success, nil
+}
+
+var _ Validator = (*verifierValidator)(nil)
+
+type validatorVerifier struct {
+	v Validator
+}
+
+// FromValidator adapts the boolean Validator v to the Verifier interface, e.g.
+// to wrap it in a FallbackVerifier or ReplayVerifier. Rejected tokens are
+// reported with ReasonUnknown, errors matching ErrInvalidCaptcha as rejected
+// tokens and all other errors as is.
+func FromValidator(v Validator) Verifier {
+	return &validatorVerifier{
+		v: v,
+	}
+}
+
+func (v *validatorVerifier) Verify(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (*Result, error) {
+	ok, err := v.v.Validate(ctx, token, clientIP)
+	if err != nil && !errors.Is(err, ErrInvalidCaptcha) {
+		return nil, err
+	}
+
+	if ok && err == nil {
+		return &Result{Success: true}, nil
+	}
+
+	return &Result{Reasons: []Reason{ReasonUnknown}}, nil
+}
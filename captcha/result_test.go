@@ -0,0 +1,101 @@
+package recaptcha_test
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+)
+
+type verifierFunc func(ctx context.Context, token string, clientIP net.IP) (*recaptcha.Result, error)
+
+func (f verifierFunc) Verify(ctx context.Context, token string, clientIP net.IP) (*recaptcha.Result, error) {
+	return f(ctx, token, clientIP)
+}
+
+type validatorFunc func(ctx context.Context, token string, clientIP net.IP) (bool, error)
+
+func (f validatorFunc) Validate(ctx context.Context, token string, clientIP net.IP) (bool, error) {
+	return f(ctx, token, clientIP)
+}
+
+var _ = Describe("Result", func() {
+	It("should report user errors of rejected tokens", func() {
+		Expect((&recaptcha.Result{Success: true}).Err()).To(Succeed())
+
+		err := (&recaptcha.Result{
+			Reasons: []recaptcha.Reason{recaptcha.ReasonTimeoutOrDuplicate},
+		}).Err()
+		Expect(err).To(MatchError(recaptcha.ErrInvalidCaptcha))
+		Expect(err).To(MatchError("captcha user error: timeout-or-duplicate"))
+	})
+
+	It("should tell configuration and transport errors from user errors", func() {
+		transportErr := errors.New("connection refused")
+
+		var err error = &recaptcha.Error{
+			Kind: recaptcha.ErrorKindTransport,
+			Err:  transportErr,
+		}
+		Expect(err).To(MatchError(transportErr))
+		Expect(err).ToNot(MatchError(recaptcha.ErrInvalidCaptcha))
+
+		err = &recaptcha.Error{
+			Kind:       recaptcha.ErrorKindConfig,
+			ErrorCodes: []string{"invalid-input-secret"},
+		}
+		Expect(err).To(MatchError("captcha config error: invalid-input-secret"))
+
+		var cErr *recaptcha.Error
+		Expect(errors.As(err, &cErr)).To(BeTrue())
+		Expect(cErr.Kind).To(Equal(recaptcha.ErrorKindConfig))
+	})
+
+	It("should adapt verifiers to the boolean validator interface", func() {
+		v := recaptcha.AsValidator(verifierFunc(func(_ context.Context, token string, _ net.IP) (*recaptcha.Result, error) {
+			if token == "error" {
+				return nil, &recaptcha.Error{Kind: recaptcha.ErrorKindTransport}
+			}
+
+			return &recaptcha.Result{Success: token == "valid"}, nil
+		}))
+
+		Expect(v.Validate(context.Background(), "valid", nil)).To(BeTrue())
+		Expect(v.Validate(context.Background(), "invalid", nil)).To(BeFalse())
+
+		_, err := v.Validate(context.Background(), "error", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should adapt boolean validators to verifiers", func() {
+		transportErr := errors.New("connection refused")
+
+		v := recaptcha.FromValidator(validatorFunc(func(_ context.Context, token string, _ net.IP) (bool, error) {
+			switch token {
+			case "error":
+				return false, transportErr
+			case "invalid":
+				return false, recaptcha.ErrInvalidCaptcha
+			}
+
+			return token == "valid", nil
+		}))
+
+		res, err := v.Verify(context.Background(), "valid", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(Equal(&recaptcha.Result{Success: true}))
+
+		for _, token := range []string{"invalid", "rejected"} {
+			res, err = v.Verify(context.Background(), token, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Success).To(BeFalse())
+			Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonUnknown}))
+		}
+
+		_, err = v.Verify(context.Background(), "error", nil)
+		Expect(err).To(MatchError(transportErr))
+	})
+})
@@ -0,0 +1,169 @@
+// Code generated by ogen, DO NOT EDIT.
+
+package client
+
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
+	return OptString{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
+func (o OptString) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptString) Reset() {
+	var v string
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptString) SetTo(v string) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptString) Get() (v string, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptString) Or(d string) string {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// Ref: #/components/schemas/SiteverifyForm
+type SiteverifyForm struct {
+	Secret   string    `json:"secret"`
+	Response string    `json:"response"`
+	Remoteip OptString `json:"remoteip"`
+	// UUID allowing to retry the verification of a token.
+	IdempotencyKey OptString `json:"idempotency_key"`
+}
+
+// GetSecret returns the value of Secret.
+func (s *SiteverifyForm) GetSecret() string {
+	return s.Secret
+}
+
+// GetResponse returns the value of Response.
+func (s *SiteverifyForm) GetResponse() string {
+	return s.Response
+}
+
+// GetRemoteip returns the value of Remoteip.
+func (s *SiteverifyForm) GetRemoteip() OptString {
+	return s.Remoteip
+}
+
+// GetIdempotencyKey returns the value of IdempotencyKey.
+func (s *SiteverifyForm) GetIdempotencyKey() OptString {
+	return s.IdempotencyKey
+}
+
+// SetSecret sets the value of Secret.
+func (s *SiteverifyForm) SetSecret(val string) {
+	s.Secret = val
+}
+
+// SetResponse sets the value of Response.
+func (s *SiteverifyForm) SetResponse(val string) {
+	s.Response = val
+}
+
+// SetRemoteip sets the value of Remoteip.
+func (s *SiteverifyForm) SetRemoteip(val OptString) {
+	s.Remoteip = val
+}
+
+// SetIdempotencyKey sets the value of IdempotencyKey.
+func (s *SiteverifyForm) SetIdempotencyKey(val OptString) {
+	s.IdempotencyKey = val
+}
+
+// Ref: #/components/schemas/SiteverifyResponse
+type SiteverifyResponse struct {
+	Success     bool      `json:"success"`
+	ChallengeTs OptString `json:"challenge_ts"`
+	Hostname    OptString `json:"hostname"`
+	Action      OptString `json:"action"`
+	// Customer data passed to the widget on the client.
+	Cdata           OptString `json:"cdata"`
+	ErrorMinusCodes []string  `json:"error-codes"`
+}
+
+// GetSuccess returns the value of Success.
+func (s *SiteverifyResponse) GetSuccess() bool {
+	return s.Success
+}
+
+// GetChallengeTs returns the value of ChallengeTs.
+func (s *SiteverifyResponse) GetChallengeTs() OptString {
+	return s.ChallengeTs
+}
+
+// GetHostname returns the value of Hostname.
+func (s *SiteverifyResponse) GetHostname() OptString {
+	return s.Hostname
+}
+
+// GetAction returns the value of Action.
+func (s *SiteverifyResponse) GetAction() OptString {
+	return s.Action
+}
+
+// GetCdata returns the value of Cdata.
+func (s *SiteverifyResponse) GetCdata() OptString {
+	return s.Cdata
+}
+
+// GetErrorMinusCodes returns the value of ErrorMinusCodes.
+func (s *SiteverifyResponse) GetErrorMinusCodes() []string {
+	return s.ErrorMinusCodes
+}
+
+// SetSuccess sets the value of Success.
+func (s *SiteverifyResponse) SetSuccess(val bool) {
+	s.Success = val
+}
+
+// SetChallengeTs sets the value of ChallengeTs.
+func (s *SiteverifyResponse) SetChallengeTs(val OptString) {
+	s.ChallengeTs = val
+}
+
+// SetHostname sets the value of Hostname.
+func (s *SiteverifyResponse) SetHostname(val OptString) {
+	s.Hostname = val
+}
+
+// SetAction sets the value of Action.
+func (s *SiteverifyResponse) SetAction(val OptString) {
+	s.Action = val
+}
+
+// SetCdata sets the value of Cdata.
+func (s *SiteverifyResponse) SetCdata(val OptString) {
+	s.Cdata = val
+}
+
+// SetErrorMinusCodes sets the value of ErrorMinusCodes.
+func (s *SiteverifyResponse) SetErrorMinusCodes(val []string) {
+	s.ErrorMinusCodes = val
+}
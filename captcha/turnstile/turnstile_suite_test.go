@@ -0,0 +1,13 @@
+package turnstile_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTurnstile(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Turnstile Suite")
+}
@@ -0,0 +1,204 @@
+package turnstile
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+	"github.com/theater-improrama/go-utils/captcha/turnstile/client"
+)
+
+// reasons maps Turnstile's error codes of user errors to reasons
+var reasons = map[string]recaptcha.Reason{
+	"missing-input-response": recaptcha.ReasonMissingInput,
+	"invalid-input-response": recaptcha.ReasonInvalidInput,
+	"timeout-or-duplicate":   recaptcha.ReasonTimeoutOrDuplicate,
+}
+
+// configErrorCodes are Turnstile's error codes caused by a misconfiguration
+var configErrorCodes = []string{
+	"missing-input-secret",
+	"invalid-input-secret",
+	"invalid-idempotency-key",
+	"bad-request",
+}
+
+// transportErrorCodes are Turnstile's error codes of failures on its side,
+// which may be retried
+var transportErrorCodes = []string{
+	"internal-error",
+}
+
+// Config configures the checks of Turnstile tokens
+type Config struct {
+	// Actions are the expected action names, any action is accepted if empty
+	Actions []string
+	// Hostnames are the allowed hostnames, any hostname is accepted if empty
+	Hostnames []string
+}
+
+type options struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type Option func(*options)
+
+// WithBaseURL sets the URL of the Turnstile API,
+// https://challenges.cloudflare.com/turnstile/v0 by default
+func WithBaseURL(u string) Option {
+	return func(o *options) {
+		o.baseURL = u
+	}
+}
+
+// WithHTTPClient sets the HTTP client of siteverify calls
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
+type idempotencyKeyCtxKey struct{}
+
+// WithIdempotencyKey returns a context making Verify send key as idempotency
+// key. Verifying a token again with the same key returns the same result
+// instead of rejecting the token as duplicate, so failed calls can be retried.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+type TurnstileValidator struct {
+	c      *client.Client
+	secret string
+	cfg    Config
+}
+
+func NewValidator(secret string, cfg Config, opts ...Option) (*TurnstileValidator, error) {
+	o := options{
+		baseURL:    "https://challenges.cloudflare.com/turnstile/v0",
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c, err := client.NewClient(o.baseURL, client.WithClient(o.httpClient))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TurnstileValidator{
+		c:      c,
+		secret: secret,
+		cfg:    cfg,
+	}, nil
+}
+
+// Verify verifies token and returns the result reported by Turnstile, with
+// Success cleared and reasons appended for failed action and hostname checks.
+// Errors are of type *recaptcha.Error.
+func (t *TurnstileValidator) Verify(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (*recaptcha.Result, error) {
+	f := &client.SiteverifyForm{
+		Response: token,
+		Secret:   t.secret,
+	}
+	if clientIP != nil {
+		f.Remoteip = client.NewOptString(clientIP.String())
+	}
+	if key, ok := ctx.Value(idempotencyKeyCtxKey{}).(string); ok {
+		f.IdempotencyKey = client.NewOptString(key)
+	}
+
+	resp, err := t.c.Siteverify(ctx, f)
+	if err != nil {
+		return nil, &recaptcha.Error{
+			Kind: recaptcha.ErrorKindTransport,
+			Err:  err,
+		}
+	}
+
+	for _, c := range resp.ErrorMinusCodes {
+		if slices.Contains(configErrorCodes, c) {
+			return nil, &recaptcha.Error{
+				Kind:       recaptcha.ErrorKindConfig,
+				ErrorCodes: resp.ErrorMinusCodes,
+			}
+		}
+
+		if slices.Contains(transportErrorCodes, c) {
+			return nil, &recaptcha.Error{
+				Kind:       recaptcha.ErrorKindTransport,
+				ErrorCodes: resp.ErrorMinusCodes,
+			}
+		}
+	}
+
+	res := &recaptcha.Result{
+		Success:    resp.Success && len(resp.ErrorMinusCodes) == 0,
+		Action:     resp.Action.Or(""),
+		Hostname:   resp.Hostname.Or(""),
+		CData:      resp.Cdata.Or(""),
+		ErrorCodes: resp.ErrorMinusCodes,
+	}
+
+	if ts, err := time.Parse(time.RFC3339, resp.ChallengeTs.Or("")); err == nil {
+		res.ChallengeTS = ts
+	}
+
+	for _, c := range resp.ErrorMinusCodes {
+		reason, ok := reasons[c]
+		if !ok {
+			reason = recaptcha.ReasonUnknown
+		}
+
+		res.Reasons = append(res.Reasons, reason)
+	}
+
+	if !res.Success && len(res.Reasons) == 0 {
+		res.Reasons = append(res.Reasons, recaptcha.ReasonUnknown)
+	}
+
+	if res.Success {
+		t.check(res)
+	}
+
+	return res, nil
+}
+
+// check applies the action and hostname checks to res
+func (t *TurnstileValidator) check(res *recaptcha.Result) {
+	if len(t.cfg.Actions) > 0 && !slices.Contains(t.cfg.Actions, res.Action) {
+		res.Reasons = append(res.Reasons, recaptcha.ReasonActionMismatch)
+	}
+
+	if len(t.cfg.Hostnames) > 0 && !slices.Contains(t.cfg.Hostnames, res.Hostname) {
+		res.Reasons = append(res.Reasons, recaptcha.ReasonHostnameMismatch)
+	}
+
+	res.Success = len(res.Reasons) == 0
+}
+
+func (t *TurnstileValidator) Validate(
+	ctx context.Context,
+	token string,
+	clientIP net.IP,
+) (bool, error) {
+	res, err := t.Verify(ctx, token, clientIP)
+	if err != nil {
+		return false, err
+	}
+
+	return res.Success, nil
+}
+
+var _ recaptcha.Verifier = (*TurnstileValidator)(nil)
+
+var _ recaptcha.Validator = (*TurnstileValidator)(nil)
@@ -0,0 +1,104 @@
+package turnstile_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recaptcha "github.com/theater-improrama/go-utils/captcha"
+	"github.com/theater-improrama/go-utils/captcha/turnstile"
+)
+
+var _ = Describe("Validator", func() {
+	var (
+		srv  *httptest.Server
+		form url.Values
+		body string
+	)
+
+	BeforeEach(func() {
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/siteverify"))
+			Expect(r.ParseForm()).To(Succeed())
+			form = r.PostForm
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		}))
+		DeferCleanup(srv.Close)
+	})
+
+	newValidator := func(cfg turnstile.Config) *turnstile.TurnstileValidator {
+		v, err := turnstile.NewValidator("secret", cfg, turnstile.WithBaseURL(srv.URL))
+		Expect(err).ToNot(HaveOccurred())
+
+		return v
+	}
+
+	It("should verify tokens and return action and cdata", func() {
+		body = `{"success":true,"challenge_ts":"2024-05-01T10:00:00.000Z","hostname":"example.com","action":"login","cdata":"session-1"}`
+
+		res, err := newValidator(turnstile.Config{
+			Actions:   []string{"login"},
+			Hostnames: []string{"example.com"},
+		}).Verify(context.Background(), "token", net.ParseIP("2001:db8::1"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeTrue())
+		Expect(res.Action).To(Equal("login"))
+		Expect(res.CData).To(Equal("session-1"))
+		Expect(res.ChallengeTS.Unix()).To(Equal(int64(1714557600)))
+
+		Expect(form.Get("secret")).To(Equal("secret"))
+		Expect(form.Get("response")).To(Equal("token"))
+		Expect(form.Get("remoteip")).To(Equal("2001:db8::1"))
+		Expect(form).ToNot(HaveKey("idempotency_key"))
+	})
+
+	It("should send the idempotency key of the context", func() {
+		body = `{"success":true}`
+
+		ctx := turnstile.WithIdempotencyKey(context.Background(), "5b2a6ad1-7c4e-4e3a-9a36-7e3a1e0c4c1f")
+		Expect(newValidator(turnstile.Config{}).Validate(ctx, "token", nil)).To(BeTrue())
+		Expect(form.Get("idempotency_key")).To(Equal("5b2a6ad1-7c4e-4e3a-9a36-7e3a1e0c4c1f"))
+	})
+
+	It("should reject tokens of unexpected actions and hostnames", func() {
+		body = `{"success":true,"hostname":"evil.example","action":"signup"}`
+
+		res, err := newValidator(turnstile.Config{
+			Actions:   []string{"login"},
+			Hostnames: []string{"example.com"},
+		}).Verify(context.Background(), "token", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Success).To(BeFalse())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{
+			recaptcha.ReasonActionMismatch,
+			recaptcha.ReasonHostnameMismatch,
+		}))
+	})
+
+	It("should map error codes to reasons and errors", func() {
+		body = `{"success":false,"error-codes":["timeout-or-duplicate"]}`
+
+		res, err := newValidator(turnstile.Config{}).Verify(context.Background(), "token", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.Reasons).To(Equal([]recaptcha.Reason{recaptcha.ReasonTimeoutOrDuplicate}))
+
+		body = `{"success":false,"error-codes":["invalid-input-secret"]}`
+
+		_, err = newValidator(turnstile.Config{}).Verify(context.Background(), "token", nil)
+		Expect(err.(*recaptcha.Error).Kind).To(Equal(recaptcha.ErrorKindConfig))
+
+		body = `{"success":false,"error-codes":["internal-error"]}`
+
+		_, err = newValidator(turnstile.Config{}).Verify(context.Background(), "token", nil)
+		Expect(err.(*recaptcha.Error).Kind).To(Equal(recaptcha.ErrorKindTransport))
+	})
+})
@@ -4,6 +4,8 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"strconv"
 
 	"github.com/theater-improrama/go-utils/jsonext"
 	"github.com/theater-improrama/go-utils/password"
@@ -12,21 +14,26 @@ import (
 
 const Type = "argon2id"
 
+// ErrUnsupportedVersion is returned when decoding a hash of an unsupported argon2 version
+var ErrUnsupportedVersion = errors.New("unsupported argon2 version")
+
+// Provide returns providers generating a fresh salt with sG for every encoded
+// hash, so a single provider may be reused and shared between goroutines. Hash
+// and Validate fail with password.ErrNoSalt.
 func Provide(sG password.SaltGenerator, p Params) password.ProviderFn {
 	return func() (password.Provider, error) {
-		s, err := sG.Generate()
-		if err != nil {
-			return nil, err
-		}
-
-		return New(
-			s,
+		v := New(
+			nil,
 			p,
-		), nil
+		)
+		v.sG = sG
+
+		return v, nil
 	}
 }
 
 type Argon2idHasherValidator struct {
+	sG     password.SaltGenerator
 	salt   []byte
 	params Params
 }
@@ -38,6 +45,11 @@ type Params struct {
 	KeyLen  uint32 `json:"key_len"`
 }
 
+// TestParams returns t=1, m=8 KiB, p=1, for tests only
+func TestParams() Params {
+	return Params{Time: 1, Memory: 8, Threads: 1, KeyLen: 32}
+}
+
 func New(
 	salt []byte,
 	params Params,
@@ -54,7 +66,57 @@ type encodableHash struct {
 	Params     Params            `json:"params"`
 }
 
+// Decode parses an argon2id hash in the PHC string format
+// ($argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>). Hashes in the
+// legacy base64 wrapped JSON encoding are accepted as well.
 func Decode(eH string) ([]byte, []byte, Params, error) {
+	if !password.IsPHC(eH) {
+		return decodeLegacy(eH)
+	}
+
+	phc, err := password.ParsePHC(eH)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	if phc.ID != Type {
+		return nil, nil, Params{}, password.ErrUnexpectedType
+	}
+
+	if phc.Version != argon2.Version {
+		return nil, nil, Params{}, ErrUnsupportedVersion
+	}
+
+	m, err := phc.UintParam("m", 32)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	t, err := phc.UintParam("t", 32)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	// the bit size of p limits it to 255 threads
+	p, err := phc.UintParam("p", 8)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	// argon2 requires at least 8 KiB of memory per thread
+	if t < 1 || p < 1 || m < 8*p || len(phc.Salt) == 0 || len(phc.Hash) == 0 {
+		return nil, nil, Params{}, password.ErrInvalidPHC
+	}
+
+	return phc.Hash, phc.Salt, Params{
+		Time:    uint32(t),
+		Memory:  uint32(m),
+		Threads: uint8(p),
+		KeyLen:  uint32(len(phc.Hash)),
+	}, nil
+}
+
+func decodeLegacy(eH string) ([]byte, []byte, Params, error) {
 	bs, err := base64.StdEncoding.DecodeString(eH)
 	if err != nil {
 		return nil, nil, Params{}, err
@@ -65,38 +127,66 @@ func Decode(eH string) ([]byte, []byte, Params, error) {
 		return nil, nil, Params{}, err
 	}
 
+	// the legacy encoding does not record the hash type, so hashes of other
+	// algorithms are told apart by their missing params
+	if h.Params.Time == 0 || h.Params.Memory == 0 || h.Params.Threads == 0 {
+		return nil, nil, Params{}, password.ErrUnexpectedType
+	}
+
 	return h.Derivative, h.Salt, h.Params, nil
 }
 
+// Encode encodes an argon2id hash in the PHC string format. The key length is
+// implied by the length of the derivative.
 func Encode(dK []byte, salt []byte, p Params) (string, error) {
-	eH := encodableHash{
-		Derivative: dK,
-		Salt:       salt,
-		Params:     p,
+	phc := password.PHC{
+		ID:      Type,
+		Version: argon2.Version,
+		Params: []password.PHCParam{
+			{Name: "m", Value: strconv.FormatUint(uint64(p.Memory), 10)},
+			{Name: "t", Value: strconv.FormatUint(uint64(p.Time), 10)},
+			{Name: "p", Value: strconv.FormatUint(uint64(p.Threads), 10)},
+		},
+		Salt: salt,
+		Hash: dK,
 	}
 
-	s, err := json.Marshal(&eH)
-	if err != nil {
-		return "", err
+	return phc.String(), nil
+}
+
+// HashEncode hashes password with a fresh salt if sG is set, the fixed salt
+// otherwise
+func (v *Argon2idHasherValidator) HashEncode(password []byte) (string, error) {
+	salt := v.salt
+
+	if v.sG != nil {
+		s, err := v.sG.Generate()
+		if err != nil {
+			return "", err
+		}
+
+		salt = s
 	}
 
-	return base64.StdEncoding.EncodeToString(s), nil
+	return v.HashEncodeSalt(password, salt)
 }
 
-func (v *Argon2idHasherValidator) HashEncode(password []byte) (string, error) {
-	dK, err := v.hash(password, v.salt)
+// HashEncodeSalt hashes password with the caller-supplied salt and encodes the hash
+func (v *Argon2idHasherValidator) HashEncodeSalt(password []byte, salt []byte) (string, error) {
+	dK, err := v.HashSalt(password, salt)
 	if err != nil {
 		return "", err
 	}
 
-	return Encode(dK, v.salt, v.params)
+	return Encode(dK, salt, v.params)
 }
 
 func (v *Argon2idHasherValidator) Type() string {
 	return Type
 }
 
-func (v *Argon2idHasherValidator) hash(password []byte, salt []byte) ([]byte, error) {
+// HashSalt hashes password with the caller-supplied salt
+func (v *Argon2idHasherValidator) HashSalt(password []byte, salt []byte) ([]byte, error) {
 	dK := argon2.IDKey(
 		password,
 		salt,
@@ -109,8 +199,13 @@ func (v *Argon2idHasherValidator) hash(password []byte, salt []byte) ([]byte, er
 	return dK, nil
 }
 
-func (v *Argon2idHasherValidator) Hash(password []byte) ([]byte, error) {
-	return v.hash(password, v.salt)
+// Hash hashes pw with the fixed salt
+func (v *Argon2idHasherValidator) Hash(pw []byte) ([]byte, error) {
+	if len(v.salt) == 0 {
+		return nil, password.ErrNoSalt
+	}
+
+	return v.HashSalt(pw, v.salt)
 }
 
 func (v *Argon2idHasherValidator) Validate(dK []byte, password []byte) (bool, error) {
@@ -133,4 +228,39 @@ func (v *Argon2idHasherValidator) Validate(dK []byte, password []byte) (bool, er
 	return true, nil
 }
 
+// EstimatedMemory returns the memory in bytes a single hash operation allocates
+func (v *Argon2idHasherValidator) EstimatedMemory() int64 {
+	return int64(v.params.Memory) * 1024
+}
+
+var _ password.MemoryEstimator = (*Argon2idHasherValidator)(nil)
+
+// NeedsRehash reports whether eH was produced with params differing from v's
+func (v *Argon2idHasherValidator) NeedsRehash(eH string) (bool, error) {
+	_, _, hP, err := Decode(eH)
+	if err != nil {
+		return false, err
+	}
+
+	return hP != v.params, nil
+}
+
+var _ password.RehashChecker = (*Argon2idHasherValidator)(nil)
+
+// Replays reports whether v hashes with a fixed salt
+func (v *Argon2idHasherValidator) Replays() bool {
+	return len(v.salt) > 0
+}
+
+var _ password.Replayer = (*Argon2idHasherValidator)(nil)
+
+// EncodePHC encodes dK with the salt and params of v
+func (v *Argon2idHasherValidator) EncodePHC(dK []byte) (string, error) {
+	return Encode(dK, v.salt, v.params)
+}
+
+var _ password.PHCEncoder = (*Argon2idHasherValidator)(nil)
+
+var _ password.SaltHasher = (*Argon2idHasherValidator)(nil)
+
 var _ password.Provider = (*Argon2idHasherValidator)(nil)
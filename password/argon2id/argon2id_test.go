@@ -0,0 +1,108 @@
+package argon2id_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/jsonext"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+)
+
+// encodeLegacy reproduces the base64 wrapped JSON encoding used before the
+// PHC string format
+func encodeLegacy(dK []byte, salt []byte, p argon2id.Params) string {
+	bs, err := json.Marshal(&struct {
+		Derivative jsonext.Base64Arr `json:"derivative"`
+		Salt       jsonext.Base64Arr `json:"salt"`
+		Params     argon2id.Params   `json:"params"`
+	}{
+		Derivative: dK,
+		Salt:       salt,
+		Params:     p,
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	return base64.StdEncoding.EncodeToString(bs)
+}
+
+var _ = Describe("Argon2id", func() {
+	params := argon2id.Params{
+		Time:    1,
+		Memory:  64,
+		Threads: 1,
+		KeyLen:  32,
+	}
+
+	It("should encode hashes in the PHC string format", func() {
+		v := argon2id.New([]byte("somesalt"), params)
+
+		eH, err := v.HashEncode([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.HasPrefix(eH, "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$")).To(BeTrue(), eH)
+
+		dK, salt, p, err := argon2id.Decode(eH)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(salt).To(Equal([]byte("somesalt")))
+		Expect(p).To(Equal(params))
+
+		Expect(argon2id.New(salt, p).Validate(dK, []byte("password"))).To(BeTrue())
+	})
+
+	It("should hash with a caller-supplied salt", func() {
+		p, err := argon2id.Provide(password.NewDefaultSaltGenerator(16), params)()
+		Expect(err).ToNot(HaveOccurred())
+
+		eH, err := p.(password.SaltHasher).HashEncodeSalt([]byte("password"), []byte("somesalt"))
+		Expect(err).ToNot(HaveOccurred())
+
+		fixed, err := argon2id.New([]byte("somesalt"), params).HashEncode([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(eH).To(Equal(fixed))
+	})
+
+	It("should decode hashes in the legacy JSON encoding", func() {
+		v := argon2id.New([]byte("somesalt"), params)
+
+		dK, err := v.Hash([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+
+		legacy := encodeLegacy(dK, []byte("somesalt"), params)
+
+		lDk, salt, p, err := argon2id.Decode(legacy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lDk).To(Equal(dK))
+		Expect(salt).To(Equal([]byte("somesalt")))
+		Expect(p).To(Equal(params))
+	})
+
+	It("should reject hashes of other algorithms", func() {
+		_, _, _, err := argon2id.Decode("$argon2i$v=19$m=64,t=1,p=1$c29tZXNhbHQ$c29tZWhhc2g")
+		Expect(err).To(MatchError(password.ErrUnexpectedType))
+	})
+
+	It("should reject unsupported versions", func() {
+		_, _, _, err := argon2id.Decode("$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$c29tZWhhc2g")
+		Expect(err).To(MatchError(argon2id.ErrUnsupportedVersion))
+	})
+
+	DescribeTable("should reject malformed PHC strings",
+		func(eH string) {
+			_, _, _, err := argon2id.Decode(eH)
+			Expect(err).To(MatchError(password.ErrInvalidPHC))
+		},
+		Entry("missing hash", "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ"),
+		Entry("empty hash", "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$"),
+		Entry("missing salt", "$argon2id$v=19$m=64,t=1,p=1"),
+		Entry("empty salt", "$argon2id$v=19$m=64,t=1,p=1$$c29tZWhhc2g"),
+		Entry("zero time", "$argon2id$v=19$m=64,t=0,p=1$c29tZXNhbHQ$c29tZWhhc2g"),
+		Entry("zero threads", "$argon2id$v=19$m=64,t=1,p=0$c29tZXNhbHQ$c29tZWhhc2g"),
+		Entry("too many threads", "$argon2id$v=19$m=4096,t=1,p=256$c29tZXNhbHQ$c29tZWhhc2g"),
+		Entry("zero memory", "$argon2id$v=19$m=0,t=1,p=1$c29tZXNhbHQ$c29tZWhhc2g"),
+		Entry("too little memory per thread", "$argon2id$v=19$m=8,t=1,p=2$c29tZXNhbHQ$c29tZWhhc2g"),
+	)
+})
@@ -0,0 +1,79 @@
+package argon2id
+
+import (
+	"errors"
+	"runtime"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// calibrationKeyLen is the key length of calibrated params
+	calibrationKeyLen = 32
+	// calibrationSaltLen is the salt length used while calibrating
+	calibrationSaltLen = 16
+	// minMemoryPerThread is the minimum memory in KiB argon2 requires per thread
+	minMemoryPerThread = 8
+)
+
+// ErrInvalidCalibration is returned for a non-positive target duration or a
+// maximum memory too small for the host's threads
+var ErrInvalidCalibration = errors.New("invalid calibration target")
+
+// ErrTargetUnreachable is returned if hashing with the minimum memory for the
+// host's threads takes longer than the target duration
+var ErrTargetUnreachable = errors.New("calibration target unreachable")
+
+// Calibrate benchmarks argon2id on the current host and returns params hashing
+// a password in at most targetDuration. Threads is set to the number of CPUs,
+// memory (in KiB) is chosen as close to maxMemory as the target allows, and
+// the remaining time budget is spent on iterations. ErrTargetUnreachable is
+// returned if the target cannot be met.
+func Calibrate(targetDuration time.Duration, maxMemory uint32) (Params, error) {
+	threads := uint8(min(runtime.NumCPU(), 255))
+	minMemory := uint32(threads) * minMemoryPerThread
+
+	if targetDuration <= 0 || maxMemory < minMemory {
+		return Params{}, ErrInvalidCalibration
+	}
+
+	p := Params{
+		Time:    1,
+		Memory:  maxMemory,
+		Threads: threads,
+		KeyLen:  calibrationKeyLen,
+	}
+
+	d := measure(p)
+	for d > targetDuration && p.Memory/2 >= minMemory {
+		p.Memory /= 2
+		d = measure(p)
+	}
+
+	if d > targetDuration {
+		return Params{}, ErrTargetUnreachable
+	}
+
+	// iterations scale linearly, so the time of a single iteration estimates
+	// how many fit into the target
+	if d > 0 {
+		p.Time = uint32(max(1, int64(targetDuration/d)))
+	}
+
+	for p.Time > 1 && measure(p) > targetDuration {
+		p.Time--
+	}
+
+	return p, nil
+}
+
+// measure returns the time it takes to hash a password with p
+func measure(p Params) time.Duration {
+	salt := make([]byte, calibrationSaltLen)
+
+	s := time.Now()
+	argon2.IDKey([]byte("calibration"), salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+
+	return time.Since(s)
+}
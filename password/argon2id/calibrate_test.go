@@ -0,0 +1,34 @@
+package argon2id_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+)
+
+var _ = Describe("Calibrate", func() {
+	It("should return params within the memory limit", func() {
+		p, err := argon2id.Calibrate(20*time.Millisecond, 4096)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(p.Time).To(BeNumerically(">=", 1))
+		Expect(p.Memory).To(BeNumerically("<=", 4096))
+		Expect(p.Threads).To(BeNumerically(">=", 1))
+		Expect(p.KeyLen).To(Equal(uint32(32)))
+	})
+
+	It("should fail for targets below the time of the minimum memory", func() {
+		_, err := argon2id.Calibrate(time.Nanosecond, 4096)
+		Expect(err).To(MatchError(argon2id.ErrTargetUnreachable))
+	})
+
+	It("should reject invalid targets", func() {
+		_, err := argon2id.Calibrate(0, 4096)
+		Expect(err).To(MatchError(argon2id.ErrInvalidCalibration))
+
+		_, err = argon2id.Calibrate(time.Second, 0)
+		Expect(err).To(MatchError(argon2id.ErrInvalidCalibration))
+	})
+})
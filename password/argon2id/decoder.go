@@ -0,0 +1,25 @@
+package argon2id
+
+import "github.com/theater-improrama/go-utils/password"
+
+// Argon2idDecoder rebuilds validators from encoded hashes for use with a password.Registry
+type Argon2idDecoder struct{}
+
+func NewDecoder() *Argon2idDecoder {
+	return &Argon2idDecoder{}
+}
+
+func (d *Argon2idDecoder) Type() string {
+	return Type
+}
+
+func (d *Argon2idDecoder) DecodeValidator(eH string) ([]byte, password.Validator, error) {
+	dK, salt, p, err := Decode(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, New(salt, p), nil
+}
+
+var _ password.Decoder = (*Argon2idDecoder)(nil)
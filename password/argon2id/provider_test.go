@@ -0,0 +1,16 @@
+package argon2id_test
+
+import (
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+	"github.com/theater-improrama/go-utils/password/passwordtest"
+)
+
+var _ = passwordtest.DescribeProvider(
+	"Argon2id",
+	argon2id.Provide(
+		password.NewDefaultSaltGenerator(16),
+		argon2id.Params{Time: 1, Memory: 64, Threads: 1, KeyLen: 32},
+	),
+	argon2id.NewDecoder(),
+)
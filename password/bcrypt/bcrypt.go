@@ -0,0 +1,120 @@
+package bcrypt
+
+import (
+	"errors"
+
+	"github.com/theater-improrama/go-utils/password"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const Type = "bcrypt"
+
+// hashLen is the length of a bcrypt hash in the modular crypt format
+const hashLen = 60
+
+// Provide returns providers hashing with the given params. bcrypt generates
+// and embeds its own salt, so no password.SaltGenerator is required.
+func Provide(p Params) password.ProviderFn {
+	return func() (password.Provider, error) {
+		return New(p), nil
+	}
+}
+
+// BcryptHasherValidator hashes passwords with bcrypt. The derivative of a
+// bcrypt hash is its modular crypt string ($2a$<cost>$<salt><hash>), which
+// embeds the salt and cost.
+type BcryptHasherValidator struct {
+	params Params
+}
+
+type Params struct {
+	Cost int `json:"cost"`
+}
+
+// TestParams returns bcrypt.MinCost, for tests only. bcrypt draws its salts
+// from crypto/rand, so its hashes are not reproducible.
+func TestParams() Params {
+	return Params{Cost: bcrypt.MinCost}
+}
+
+func New(p Params) *BcryptHasherValidator {
+	return &BcryptHasherValidator{
+		params: p,
+	}
+}
+
+// Decode parses a bcrypt hash in the modular crypt format
+// ($2a$<cost>$<salt><hash>, $2b$ and $2y$ prefixes are accepted as well)
+func Decode(eH string) ([]byte, Params, error) {
+	dK := []byte(eH)
+
+	c, err := bcrypt.Cost(dK)
+	if err != nil {
+		return nil, Params{}, err
+	}
+
+	return dK, Params{
+		Cost: c,
+	}, nil
+}
+
+// Encode encodes a bcrypt hash. As the derivative already is in the modular
+// crypt format, it is returned as is.
+func Encode(dK []byte) (string, error) {
+	return string(dK), nil
+}
+
+func (v *BcryptHasherValidator) Type() string {
+	return Type
+}
+
+// Identifiers returns the modular crypt prefixes of bcrypt hashes
+func (v *BcryptHasherValidator) Identifiers() []string {
+	return identifiers
+}
+
+func (v *BcryptHasherValidator) HashEncode(password []byte) (string, error) {
+	dK, err := v.Hash(password)
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(dK)
+}
+
+func (v *BcryptHasherValidator) Hash(password []byte) ([]byte, error) {
+	return bcrypt.GenerateFromPassword(password, v.params.Cost)
+}
+
+func (v *BcryptHasherValidator) Validate(dK []byte, password []byte) (bool, error) {
+	// bcrypt ignores data trailing a valid hash
+	if len(dK) != hashLen {
+		return false, nil
+	}
+
+	err := bcrypt.CompareHashAndPassword(dK, password)
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// NeedsRehash reports whether eH was produced with params differing from v's
+func (v *BcryptHasherValidator) NeedsRehash(eH string) (bool, error) {
+	_, hP, err := Decode(eH)
+	if err != nil {
+		return false, err
+	}
+
+	return hP != v.params, nil
+}
+
+var _ password.RehashChecker = (*BcryptHasherValidator)(nil)
+
+var _ password.Identifier = (*BcryptHasherValidator)(nil)
+
+var _ password.Provider = (*BcryptHasherValidator)(nil)
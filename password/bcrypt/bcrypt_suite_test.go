@@ -0,0 +1,13 @@
+package bcrypt_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBcrypt(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bcrypt Suite")
+}
@@ -0,0 +1,57 @@
+package bcrypt_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/bcrypt"
+)
+
+var _ = Describe("Bcrypt", func() {
+	params := bcrypt.Params{
+		Cost: 4,
+	}
+
+	It("should hash in the modular crypt format", func() {
+		eH, err := bcrypt.New(params).HashEncode([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.HasPrefix(eH, "$2a$04$")).To(BeTrue(), eH)
+
+		dK, p, err := bcrypt.Decode(eH)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p).To(Equal(params))
+
+		v := bcrypt.New(p)
+		Expect(v.Validate(dK, []byte("password"))).To(BeTrue())
+		Expect(v.Validate(dK, []byte("wrong"))).To(BeFalse())
+	})
+
+	It("should validate hashes produced by other implementations", func() {
+		dK, p, err := bcrypt.Decode("$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.Cost).To(Equal(5))
+
+		Expect(bcrypt.New(p).Validate(dK, []byte("U*U"))).To(BeTrue())
+	})
+
+	It("should be detected by the registry for every prefix", func() {
+		r := password.NewRegistry(bcrypt.NewDecoder())
+
+		for _, eH := range []string{
+			"$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+			"$2b$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+			"$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+		} {
+			Expect(r.Verify(eH, []byte("U*U"))).To(BeTrue(), eH)
+		}
+	})
+
+	It("should flag hashes with another cost for rehashing", func() {
+		policy := password.NewRehashPolicy(bcrypt.Provide(params))
+
+		Expect(policy.NeedsRehash("$2b$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW")).To(BeTrue())
+		Expect(policy.NeedsRehash("$2b$04$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW")).To(BeFalse())
+	})
+})
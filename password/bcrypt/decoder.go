@@ -0,0 +1,34 @@
+package bcrypt
+
+import "github.com/theater-improrama/go-utils/password"
+
+var identifiers = []string{"2a", "2b", "2y"}
+
+// BcryptDecoder rebuilds validators from encoded hashes for use with a password.Registry
+type BcryptDecoder struct{}
+
+func NewDecoder() *BcryptDecoder {
+	return &BcryptDecoder{}
+}
+
+func (d *BcryptDecoder) Type() string {
+	return Type
+}
+
+// Identifiers returns the modular crypt prefixes of bcrypt hashes
+func (d *BcryptDecoder) Identifiers() []string {
+	return identifiers
+}
+
+func (d *BcryptDecoder) DecodeValidator(eH string) ([]byte, password.Validator, error) {
+	dK, p, err := Decode(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, New(p), nil
+}
+
+var _ password.Identifier = (*BcryptDecoder)(nil)
+
+var _ password.Decoder = (*BcryptDecoder)(nil)
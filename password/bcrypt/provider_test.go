@@ -0,0 +1,12 @@
+package bcrypt_test
+
+import (
+	"github.com/theater-improrama/go-utils/password/bcrypt"
+	"github.com/theater-improrama/go-utils/password/passwordtest"
+)
+
+var _ = passwordtest.DescribeProvider(
+	"Bcrypt",
+	bcrypt.Provide(bcrypt.Params{Cost: 4}),
+	bcrypt.NewDecoder(),
+)
@@ -0,0 +1,309 @@
+package password
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// TypeEncrypted is the type of hashes encrypted at rest with an application
+// key, so a dump of the stored hashes alone cannot be attacked offline. They
+// are encoded as PHC strings
+//
+//	$aead$c=<cipher>,keyid=<key id>$<nonce>$<ciphertext>
+//
+// where the ciphertext is the encrypted encoded inner hash. The cipher and key
+// ID are authenticated as additional data.
+const TypeEncrypted = "aead"
+
+// Cipher is an AEAD cipher encrypting hashes at rest
+type Cipher string
+
+const (
+	// CipherAESGCM is AES-GCM with a random 96 bit nonce, the key length
+	// selects AES-128, AES-192 or AES-256
+	CipherAESGCM Cipher = "aes-gcm"
+	// CipherXChaCha20Poly1305 is XChaCha20-Poly1305 with a random 192 bit
+	// nonce and a 256 bit key
+	CipherXChaCha20Poly1305 Cipher = "xchacha20-poly1305"
+)
+
+const (
+	encryptedCipherParam = "c"
+	encryptedKeyIDParam  = "keyid"
+)
+
+var (
+	// ErrUnsupportedCipher is returned for ciphers other than CipherAESGCM and CipherXChaCha20Poly1305
+	ErrUnsupportedCipher = errors.New("unsupported cipher")
+	// ErrDecryptionFailed is returned when an encrypted hash was tampered with
+	// or encrypted with another key
+	ErrDecryptionFailed = errors.New("hash decryption failed")
+)
+
+func newAEAD(c Cipher, key []byte) (cipher.AEAD, error) {
+	switch c {
+	case CipherAESGCM:
+		b, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return cipher.NewGCM(b)
+	case CipherXChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	default:
+		return nil, ErrUnsupportedCipher
+	}
+}
+
+// encrypt encrypts eH with c under the current key of kr
+func encrypt(c Cipher, kr *Keyring, eH string) (string, error) {
+	id, key := kr.Current()
+
+	a, err := newAEAD(c, key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, a.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	phc := PHC{
+		ID: TypeEncrypted,
+		Params: []PHCParam{
+			{Name: encryptedCipherParam, Value: string(c)},
+			{Name: encryptedKeyIDParam, Value: id},
+		},
+	}
+
+	// the cipher and key ID are authenticated, so they cannot be swapped
+	ad := []byte(phc.String())
+
+	phc.Salt = nonce
+	phc.Hash = a.Seal(nil, nonce, []byte(eH), ad)
+
+	return phc.String(), nil
+}
+
+// decrypt returns the inner hash of the encrypted hash eH together with the
+// cipher and key ID it was encrypted with
+func decrypt(kr *Keyring, eH string) (string, Cipher, string, error) {
+	phc, err := ParsePHC(eH)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if phc.ID != TypeEncrypted {
+		return "", "", "", ErrUnexpectedType
+	}
+
+	c, ok := phc.Param(encryptedCipherParam)
+	if !ok {
+		return "", "", "", ErrInvalidPHC
+	}
+
+	id, ok := phc.Param(encryptedKeyIDParam)
+	if !ok {
+		return "", "", "", ErrInvalidPHC
+	}
+
+	key, err := kr.Key(id)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	a, err := newAEAD(Cipher(c), key)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	nonce, ct := phc.Salt, phc.Hash
+	if len(nonce) != a.NonceSize() || ct == nil {
+		return "", "", "", ErrInvalidPHC
+	}
+
+	phc.Salt, phc.Hash = nil, nil
+
+	iEH, err := a.Open(nil, nonce, ct, []byte(phc.String()))
+	if err != nil {
+		return "", "", "", ErrDecryptionFailed
+	}
+
+	return string(iEH), Cipher(c), id, nil
+}
+
+// EncryptedProvider encrypts the hashes encoded by the wrapped provider with
+// the current key of a keyring
+type EncryptedProvider struct {
+	p  Provider
+	c  Cipher
+	kr *Keyring
+}
+
+// Encrypt returns providers encrypting the hashes encoded by the providers
+// returned by pFn with c under the current key of kr
+func Encrypt(pFn ProviderFn, c Cipher, kr *Keyring) ProviderFn {
+	return func() (Provider, error) {
+		p, err := pFn()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewEncryptedProvider(p, c, kr), nil
+	}
+}
+
+func NewEncryptedProvider(p Provider, c Cipher, kr *Keyring) *EncryptedProvider {
+	return &EncryptedProvider{
+		p:  p,
+		c:  c,
+		kr: kr,
+	}
+}
+
+func (p *EncryptedProvider) Type() string {
+	return TypeEncrypted
+}
+
+func (p *EncryptedProvider) HashEncode(password []byte) (string, error) {
+	eH, err := p.p.HashEncode(password)
+	if err != nil {
+		return "", err
+	}
+
+	return encrypt(p.c, p.kr, eH)
+}
+
+// Hash returns the derivative of the wrapped provider, only the encoded hash
+// is encrypted
+func (p *EncryptedProvider) Hash(password []byte) ([]byte, error) {
+	return p.p.Hash(password)
+}
+
+func (p *EncryptedProvider) Validate(dK []byte, password []byte) (bool, error) {
+	return p.p.Validate(dK, password)
+}
+
+// EstimatedMemory returns the estimate of the wrapped provider
+func (p *EncryptedProvider) EstimatedMemory() int64 {
+	return estimatedMemory(p.p)
+}
+
+// NeedsRehash reports whether eH is not encrypted, or its inner hash needs to
+// be rehashed according to the wrapped provider. Hashes encrypted with another
+// cipher or a retired key are re-encrypted by a Reencrypter instead.
+func (p *EncryptedProvider) NeedsRehash(eH string) (bool, error) {
+	if !IsPHC(eH) || phcID(eH) != TypeEncrypted {
+		return true, nil
+	}
+
+	iEH, _, _, err := decrypt(p.kr, eH)
+	if err != nil {
+		return false, err
+	}
+
+	return NewRehashPolicy(func() (Provider, error) {
+		return p.p, nil
+	}).NeedsRehash(iEH)
+}
+
+var _ MemoryEstimator = (*EncryptedProvider)(nil)
+
+var _ RehashChecker = (*EncryptedProvider)(nil)
+
+var _ Provider = (*EncryptedProvider)(nil)
+
+// EncryptedDecoder decrypts encrypted hashes, looking up the key by the ID
+// recorded in the hash, and decodes the inner hash with a registry. Register
+// it with the same registry to verify encrypted hashes along with all other
+// types.
+type EncryptedDecoder struct {
+	r  *Registry
+	kr *Keyring
+}
+
+func NewEncryptedDecoder(r *Registry, kr *Keyring) *EncryptedDecoder {
+	return &EncryptedDecoder{
+		r:  r,
+		kr: kr,
+	}
+}
+
+func (d *EncryptedDecoder) Type() string {
+	return TypeEncrypted
+}
+
+func (d *EncryptedDecoder) DecodeValidator(eH string) ([]byte, Validator, error) {
+	iEH, _, _, err := decrypt(d.kr, eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return d.r.Decode(iEH)
+}
+
+var _ Decoder = (*EncryptedDecoder)(nil)
+
+// Reencrypter rotates the encryption of stored hashes without knowing the
+// passwords
+type Reencrypter struct {
+	c  Cipher
+	kr *Keyring
+}
+
+// NewReencrypter returns a reencrypter encrypting hashes with c under the
+// current key of kr
+func NewReencrypter(c Cipher, kr *Keyring) *Reencrypter {
+	return &Reencrypter{
+		c:  c,
+		kr: kr,
+	}
+}
+
+// Reencrypt decrypts eH with the key it was encrypted with and encrypts the
+// inner hash with the current key. Hashes already encrypted with the current
+// key and cipher are returned unchanged, hashes which are not encrypted are
+// encrypted.
+func (r *Reencrypter) Reencrypt(eH string) (string, error) {
+	if !IsPHC(eH) || phcID(eH) != TypeEncrypted {
+		return encrypt(r.c, r.kr, eH)
+	}
+
+	iEH, c, id, err := decrypt(r.kr, eH)
+	if err != nil {
+		return "", err
+	}
+
+	if c == r.c && !r.kr.IsRetired(id) {
+		return eH, nil
+	}
+
+	return encrypt(r.c, r.kr, iEH)
+}
+
+// ReencryptBatch reencrypts every hash of eHs. The reencrypted hash and the
+// error of eHs[i] are returned at index i, failed hashes are returned
+// unchanged.
+func (r *Reencrypter) ReencryptBatch(eHs []string) ([]string, []error) {
+	res := make([]string, len(eHs))
+	errs := make([]error, len(eHs))
+
+	for i, eH := range eHs {
+		rEH, err := r.Reencrypt(eH)
+		if err != nil {
+			res[i] = eH
+			errs[i] = err
+			continue
+		}
+
+		res[i] = rEH
+	}
+
+	return res, errs
+}
@@ -0,0 +1,147 @@
+package password_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+var _ = Describe("Encrypt", func() {
+	sG := password.NewDefaultSaltGenerator(16)
+
+	keys := map[string][]byte{
+		"v1": bytes.Repeat([]byte{1}, 32),
+		"v2": bytes.Repeat([]byte{2}, 32),
+	}
+
+	var (
+		old *password.Keyring
+		kr  *password.Keyring
+		r   *password.Registry
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		old, err = password.NewKeyring("v1", keys)
+		Expect(err).ToNot(HaveOccurred())
+
+		kr, err = password.NewKeyring("v2", keys)
+		Expect(err).ToNot(HaveOccurred())
+
+		r = password.NewRegistry(
+			argon2id.NewDecoder(),
+			pbkdf2.NewDecoder(),
+		)
+		r.Register(password.NewEncryptedDecoder(r, kr))
+	})
+
+	hashEncode := func(pFn password.ProviderFn) string {
+		p, err := pFn()
+		Expect(err).ToNot(HaveOccurred())
+
+		eH, err := p.HashEncode([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+
+		return eH
+	}
+
+	DescribeTable("should encrypt hashes and verify them",
+		func(c password.Cipher) {
+			eH := hashEncode(password.Encrypt(argon2id.Provide(sG, argon2id.TestParams()), c, kr))
+			Expect(eH).To(HavePrefix("$aead$c=" + string(c) + ",keyid=v2$"))
+			Expect(eH).ToNot(ContainSubstring("argon2id"))
+
+			Expect(r.Verify(eH, []byte("password"))).To(BeTrue())
+			Expect(r.Verify(eH, []byte("wrong"))).To(BeFalse())
+		},
+		Entry("AES-GCM", password.CipherAESGCM),
+		Entry("XChaCha20-Poly1305", password.CipherXChaCha20Poly1305),
+	)
+
+	It("should fail to hash and validate without a fixed salt", func() {
+		p, err := password.Encrypt(argon2id.Provide(sG, argon2id.TestParams()), password.CipherAESGCM, kr)()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = p.Hash([]byte("password"))
+		Expect(err).To(MatchError(password.ErrNoSalt))
+
+		_, err = p.Validate([]byte("derivative"), []byte("password"))
+		Expect(err).To(MatchError(password.ErrNoSalt))
+	})
+
+	It("should reject tampered hashes", func() {
+		eH := hashEncode(password.Encrypt(pbkdf2.Provide(sG, pbkdf2.TestParams()), password.CipherAESGCM, kr))
+
+		phc, err := password.ParsePHC(eH)
+		Expect(err).ToNot(HaveOccurred())
+
+		phc.Hash[0] ^= 1
+		_, err = r.Verify(phc.String(), []byte("password"))
+		Expect(err).To(MatchError(password.ErrDecryptionFailed))
+
+		phc.Hash[0] ^= 1
+		phc.Params[1].Value = "v1"
+		_, err = r.Verify(phc.String(), []byte("password"))
+		Expect(err).To(MatchError(password.ErrDecryptionFailed))
+	})
+
+	It("should fail for unsupported ciphers and invalid keys", func() {
+		p, err := password.Encrypt(pbkdf2.Provide(sG, pbkdf2.TestParams()), "rot13", kr)()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = p.HashEncode([]byte("password"))
+		Expect(err).To(MatchError(password.ErrUnsupportedCipher))
+
+		short, err := password.NewKeyring("v1", map[string][]byte{"v1": []byte("short")})
+		Expect(err).ToNot(HaveOccurred())
+
+		p, err = password.Encrypt(pbkdf2.Provide(sG, pbkdf2.TestParams()), password.CipherXChaCha20Poly1305, short)()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = p.HashEncode([]byte("password"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reencrypt hashes with the current key without the password", func() {
+		plain := hashEncode(pbkdf2.Provide(sG, pbkdf2.TestParams()))
+		retired := hashEncode(password.Encrypt(pbkdf2.Provide(sG, pbkdf2.TestParams()), password.CipherAESGCM, old))
+		current := hashEncode(password.Encrypt(pbkdf2.Provide(sG, pbkdf2.TestParams()), password.CipherXChaCha20Poly1305, kr))
+
+		res, errs := password.NewReencrypter(password.CipherXChaCha20Poly1305, kr).ReencryptBatch([]string{
+			plain,
+			retired,
+			current,
+			"$aead$c=aes-gcm,keyid=v3$AAAAAAAAAAAAAAAA$AAAA",
+		})
+		Expect(errs[:3]).To(HaveEach(BeNil()))
+		Expect(errs[3]).To(MatchError(password.ErrUnknownKey))
+
+		Expect(res[0]).To(HavePrefix("$aead$c=xchacha20-poly1305,keyid=v2$"))
+		Expect(res[1]).To(HavePrefix("$aead$c=xchacha20-poly1305,keyid=v2$"))
+		Expect(res[2]).To(Equal(current))
+		Expect(res[3]).To(Equal("$aead$c=aes-gcm,keyid=v3$AAAAAAAAAAAAAAAA$AAAA"))
+
+		for _, eH := range res[:3] {
+			Expect(r.Verify(eH, []byte("password"))).To(BeTrue(), eH)
+		}
+	})
+
+	It("should flag unencrypted and outdated inner hashes for rehashing", func() {
+		preferred := password.Encrypt(pbkdf2.Provide(sG, pbkdf2.TestParams()), password.CipherAESGCM, kr)
+		policy := password.NewRehashPolicy(preferred)
+
+		Expect(policy.NeedsRehash(hashEncode(preferred))).To(BeFalse())
+		Expect(policy.NeedsRehash(hashEncode(pbkdf2.Provide(sG, pbkdf2.TestParams())))).To(BeTrue())
+
+		weaker := password.Encrypt(pbkdf2.Provide(sG, pbkdf2.Params{Iter: 2, KeyLen: 32}), password.CipherAESGCM, kr)
+		Expect(policy.NeedsRehash(hashEncode(weaker))).To(BeTrue())
+
+		other := password.Encrypt(argon2id.Provide(sG, argon2id.TestParams()), password.CipherAESGCM, kr)
+		Expect(policy.NeedsRehash(hashEncode(other))).To(BeTrue())
+	})
+})
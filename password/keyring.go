@@ -0,0 +1,67 @@
+package password
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrUnknownKey is returned when a keyring holds no key for the requested ID
+	ErrUnknownKey = errors.New("unknown key")
+	// ErrInvalidKeyID is returned for key IDs which cannot be stored in a PHC string
+	ErrInvalidKeyID = errors.New("invalid key id")
+)
+
+// Keyring holds secret keys by ID. New secrets are created with the current
+// key, all other keys are retired and only kept for verifying existing secrets.
+type Keyring struct {
+	current string
+	keys    map[string][]byte
+}
+
+// NewKeyring returns a keyring of keys whose current key has the ID current.
+// Key IDs may only consist of the characters [A-Za-z0-9/+.-].
+func NewKeyring(current string, keys map[string][]byte) (*Keyring, error) {
+	for id := range keys {
+		if !isKeyID(id) {
+			return nil, ErrInvalidKeyID
+		}
+	}
+
+	if _, ok := keys[current]; !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return &Keyring{
+		current: current,
+		keys:    keys,
+	}, nil
+}
+
+func isKeyID(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	return strings.Trim(id, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789/+.-") == ""
+}
+
+// Current returns the ID and key of the current key
+func (k *Keyring) Current() (string, []byte) {
+	return k.current, k.keys[k.current]
+}
+
+// Key returns the key with the given ID
+func (k *Keyring) Key(id string) ([]byte, error) {
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// IsRetired reports whether id is not the ID of the current key
+func (k *Keyring) IsRetired(id string) bool {
+	return id != k.current
+}
@@ -0,0 +1,59 @@
+// Package legacy verifies password hashes imported from legacy systems. The
+// validators refuse to hash new passwords with password.ErrVerifyOnly; rehash
+// passwords with a current provider after a successful login instead.
+package legacy
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/theater-improrama/go-utils/password"
+)
+
+// ErrInvalidHash is returned for malformed hashes of a legacy format
+var ErrInvalidHash = errors.New("invalid legacy hash")
+
+// verifyOnly implements the hashing methods of password.Provider for
+// validators of legacy formats
+type verifyOnly struct{}
+
+func (verifyOnly) HashEncode([]byte) (string, error) {
+	return "", password.ErrVerifyOnly
+}
+
+func (verifyOnly) Hash([]byte) ([]byte, error) {
+	return nil, password.ErrVerifyOnly
+}
+
+// equal compares two derivatives in constant time
+func equal(dK []byte, pDk []byte) bool {
+	if subtle.ConstantTimeEq(int32(len(dK)), int32(len(pDk))) == 0 {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(dK, pDk) == 1
+}
+
+// cryptAlphabet is the base64 alphabet of the crypt(3) family
+const cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// cryptEncode encodes groups of three bytes, given by their indices into b,
+// in the crypt(3) base64 variant. The last group may consist of fewer bytes.
+func cryptEncode(b []byte, groups [][]int) []byte {
+	var out []byte
+
+	for _, g := range groups {
+		var w uint32
+		for _, i := range g {
+			w = w<<8 | uint32(b[i])
+		}
+
+		n := len(g) + 1
+		for range n {
+			out = append(out, cryptAlphabet[w&0x3f])
+			w >>= 6
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,58 @@
+package legacy_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/legacy"
+)
+
+var _ = Describe("Legacy", func() {
+	r := password.NewRegistry(
+		legacy.NewMD5CryptDecoder(),
+		legacy.NewSHACryptDecoder(),
+		legacy.NewDjangoPbkdf2Decoder(),
+		legacy.NewWerkzeugPbkdf2Decoder(),
+	)
+
+	DescribeTable("should verify hashes produced by other implementations",
+		func(eH string, pw string, typ string) {
+			dK, v, err := r.Decode(eH)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(v.Type()).To(Equal(typ))
+
+			Expect(v.Validate(dK, []byte(pw))).To(BeTrue())
+			Expect(v.Validate(dK, []byte(pw+"x"))).To(BeFalse())
+		},
+		Entry("MD5-crypt", "$1$saltsalt$qjXMvbEw8oaL.CzflDtaK/", "password", legacy.TypeMD5Crypt),
+		Entry("MD5-crypt with empty password", "$1$ab$rn6aQS/o7141mj179E/zA.", "", legacy.TypeMD5Crypt),
+		Entry("SHA256-crypt", "$5$saltsaltsaltsalt$WsFBeg1qQ90JL3VkUTuM7xVV/5njhLngIVm6ftSnBR2", "password", legacy.TypeSHA256Crypt),
+		Entry("SHA512-crypt with rounds", "$6$rounds=10000$saltsalt$ZqOTO2O04D/DgwZlm.rZTgWxvBaIf4LQsZKtXFEu9UHJ4CvgmdLAGxKUzJ0mPO98OevETdY6oK/Oac6j2Axxq/", "password", legacy.TypeSHA512Crypt),
+		Entry("SHA512-crypt with long password", "$6$saltsalt$FlLicwScC7rsCSYaza8xZOAsJOU3/c2eb2mdNCBVC9AMuZTIpZW6dH9VMfQb.jW3LK2uor8GG1WI.faY0r4B80", "pässwörd longer than sixteen bytes", legacy.TypeSHA512Crypt),
+		Entry("Django PBKDF2-SHA256", "pbkdf2_sha256$1000$saltsalt$E196ZhRPzw+wA84EjzHwJO1cv/MFJdO6C/sxmUeTYqY=", "password", legacy.TypeDjangoPbkdf2),
+		Entry("Django PBKDF2-SHA1", "pbkdf2_sha1$1000$saltsalt$6f6/9Uv85mj94wGsyFVjzJ3HHvY=", "password", legacy.TypeDjangoPbkdf2),
+		Entry("Werkzeug PBKDF2-SHA256", "pbkdf2:sha256:1000$saltsalt$135f7a66144fcf0fb003ce048f31f024ed5cbff30525d3ba0bfb3199479362a6", "password", legacy.TypeWerkzeugPbkdf2),
+	)
+
+	It("should refuse to hash new passwords", func() {
+		v := legacy.NewMD5Crypt([]byte("saltsalt"))
+
+		_, err := v.HashEncode([]byte("password"))
+		Expect(err).To(MatchError(password.ErrVerifyOnly))
+
+		_, err = v.Hash([]byte("password"))
+		Expect(err).To(MatchError(password.ErrVerifyOnly))
+	})
+
+	It("should reject malformed hashes", func() {
+		for _, eH := range []string{
+			"$1$saltsalt$tooshort",
+			"$5$rounds=x$saltsalt$WsFBeg1qQ90JL3VkUTuM7xVV/5njhLngIVm6ftSnBR2",
+			"pbkdf2_sha256$1000$saltsalt",
+			"pbkdf2:sha256$saltsalt$135f7a66",
+		} {
+			_, err := r.Verify(eH, []byte("password"))
+			Expect(err).To(HaveOccurred(), eH)
+		}
+	})
+})
@@ -0,0 +1,142 @@
+package legacy
+
+import (
+	"crypto/md5"
+	"strings"
+
+	"github.com/theater-improrama/go-utils/password"
+)
+
+const TypeMD5Crypt = "md5-crypt"
+
+const (
+	md5CryptMagic      = "$1$"
+	md5CryptMaxSaltLen = 8
+)
+
+var md5CryptGroups = [][]int{
+	{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}, {11},
+}
+
+// MD5CryptValidator verifies MD5-crypt hashes ($1$<salt>$<hash>) as produced
+// by crypt(3) and openssl passwd -1
+type MD5CryptValidator struct {
+	verifyOnly
+	salt []byte
+}
+
+func NewMD5Crypt(salt []byte) *MD5CryptValidator {
+	return &MD5CryptValidator{
+		salt: salt,
+	}
+}
+
+// DecodeMD5Crypt parses an MD5-crypt hash and returns the encoded hash part as
+// derivative together with the salt
+func DecodeMD5Crypt(eH string) ([]byte, []byte, error) {
+	rest, ok := strings.CutPrefix(eH, md5CryptMagic)
+	if !ok {
+		return nil, nil, password.ErrUnexpectedType
+	}
+
+	salt, h, ok := strings.Cut(rest, "$")
+	if !ok || len(salt) > md5CryptMaxSaltLen || len(h) != 22 {
+		return nil, nil, ErrInvalidHash
+	}
+
+	return []byte(h), []byte(salt), nil
+}
+
+func (v *MD5CryptValidator) Type() string {
+	return TypeMD5Crypt
+}
+
+func (v *MD5CryptValidator) Validate(dK []byte, password []byte) (bool, error) {
+	return equal(dK, md5Crypt(password, v.salt)), nil
+}
+
+func md5Crypt(pw []byte, salt []byte) []byte {
+	alt := md5.New()
+	alt.Write(pw)
+	alt.Write(salt)
+	alt.Write(pw)
+	altSum := alt.Sum(nil)
+
+	ctx := md5.New()
+	ctx.Write(pw)
+	ctx.Write([]byte(md5CryptMagic))
+	ctx.Write(salt)
+
+	for pl := len(pw); pl > 0; pl -= md5.Size {
+		ctx.Write(altSum[:min(pl, md5.Size)])
+	}
+
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			ctx.Write([]byte{0})
+		} else {
+			ctx.Write(pw[:1])
+		}
+	}
+
+	final := ctx.Sum(nil)
+
+	for i := range 1000 {
+		c := md5.New()
+
+		if i&1 != 0 {
+			c.Write(pw)
+		} else {
+			c.Write(final)
+		}
+
+		if i%3 != 0 {
+			c.Write(salt)
+		}
+
+		if i%7 != 0 {
+			c.Write(pw)
+		}
+
+		if i&1 != 0 {
+			c.Write(final)
+		} else {
+			c.Write(pw)
+		}
+
+		final = c.Sum(nil)
+	}
+
+	return cryptEncode(final, md5CryptGroups)
+}
+
+var _ password.Provider = (*MD5CryptValidator)(nil)
+
+// MD5CryptDecoder rebuilds validators from MD5-crypt hashes for use with a password.Registry
+type MD5CryptDecoder struct{}
+
+func NewMD5CryptDecoder() *MD5CryptDecoder {
+	return &MD5CryptDecoder{}
+}
+
+func (d *MD5CryptDecoder) Type() string {
+	return TypeMD5Crypt
+}
+
+// Identifiers returns the modular crypt prefix of MD5-crypt hashes
+func (d *MD5CryptDecoder) Identifiers() []string {
+	return []string{"1"}
+}
+
+func (d *MD5CryptDecoder) DecodeValidator(eH string) ([]byte, password.Validator, error) {
+	dK, salt, err := DecodeMD5Crypt(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, NewMD5Crypt(salt), nil
+}
+
+var _ password.Identifier = (*MD5CryptDecoder)(nil)
+
+var _ password.Decoder = (*MD5CryptDecoder)(nil)
@@ -0,0 +1,178 @@
+package legacy
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+const (
+	TypeDjangoPbkdf2   = "django-pbkdf2"
+	TypeWerkzeugPbkdf2 = "werkzeug-pbkdf2"
+)
+
+const (
+	djangoPrefix   = "pbkdf2_"
+	werkzeugPrefix = "pbkdf2:"
+)
+
+// Pbkdf2Validator verifies PBKDF2 hashes of Django
+// (pbkdf2_<digest>$<iter>$<salt>$<base64 hash>) and Werkzeug
+// (pbkdf2:<digest>:<iter>$<salt>$<hex hash>). Both use the salt string as is.
+type Pbkdf2Validator struct {
+	verifyOnly
+	typ string
+	v   *pbkdf2.Pbkdf2HasherValidator
+}
+
+func newPbkdf2(typ string, salt []byte, p pbkdf2.Params) (*Pbkdf2Validator, error) {
+	v, err := pbkdf2.New(salt, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pbkdf2Validator{
+		typ: typ,
+		v:   v,
+	}, nil
+}
+
+// DecodeDjangoPbkdf2 parses a Django PBKDF2 hash
+func DecodeDjangoPbkdf2(eH string) ([]byte, []byte, pbkdf2.Params, error) {
+	rest, ok := strings.CutPrefix(eH, djangoPrefix)
+	if !ok {
+		return nil, nil, pbkdf2.Params{}, password.ErrUnexpectedType
+	}
+
+	fields := strings.Split(rest, "$")
+	if len(fields) != 4 {
+		return nil, nil, pbkdf2.Params{}, ErrInvalidHash
+	}
+
+	iter, err := strconv.Atoi(fields[1])
+	if err != nil || iter <= 0 {
+		return nil, nil, pbkdf2.Params{}, ErrInvalidHash
+	}
+
+	dK, err := base64.StdEncoding.DecodeString(fields[3])
+	if err != nil {
+		return nil, nil, pbkdf2.Params{}, ErrInvalidHash
+	}
+
+	return dK, []byte(fields[2]), pbkdf2.Params{
+		KeyLen: len(dK),
+		Iter:   iter,
+		Digest: fields[0],
+	}, nil
+}
+
+// DecodeWerkzeugPbkdf2 parses a Werkzeug PBKDF2 hash. Hashes without explicit
+// iteration count are rejected, as their count depends on the Werkzeug version.
+func DecodeWerkzeugPbkdf2(eH string) ([]byte, []byte, pbkdf2.Params, error) {
+	rest, ok := strings.CutPrefix(eH, werkzeugPrefix)
+	if !ok {
+		return nil, nil, pbkdf2.Params{}, password.ErrUnexpectedType
+	}
+
+	fields := strings.Split(rest, "$")
+	if len(fields) != 3 {
+		return nil, nil, pbkdf2.Params{}, ErrInvalidHash
+	}
+
+	digest, iterS, ok := strings.Cut(fields[0], ":")
+	if !ok {
+		return nil, nil, pbkdf2.Params{}, ErrInvalidHash
+	}
+
+	iter, err := strconv.Atoi(iterS)
+	if err != nil || iter <= 0 {
+		return nil, nil, pbkdf2.Params{}, ErrInvalidHash
+	}
+
+	dK, err := hex.DecodeString(fields[2])
+	if err != nil {
+		return nil, nil, pbkdf2.Params{}, ErrInvalidHash
+	}
+
+	return dK, []byte(fields[1]), pbkdf2.Params{
+		KeyLen: len(dK),
+		Iter:   iter,
+		Digest: digest,
+	}, nil
+}
+
+func NewDjangoPbkdf2(salt []byte, p pbkdf2.Params) (*Pbkdf2Validator, error) {
+	return newPbkdf2(TypeDjangoPbkdf2, salt, p)
+}
+
+func NewWerkzeugPbkdf2(salt []byte, p pbkdf2.Params) (*Pbkdf2Validator, error) {
+	return newPbkdf2(TypeWerkzeugPbkdf2, salt, p)
+}
+
+func (v *Pbkdf2Validator) Type() string {
+	return v.typ
+}
+
+func (v *Pbkdf2Validator) Validate(dK []byte, password []byte) (bool, error) {
+	return v.v.Validate(dK, password)
+}
+
+var _ password.Provider = (*Pbkdf2Validator)(nil)
+
+// DjangoPbkdf2Decoder rebuilds validators from Django PBKDF2 hashes for use with a password.Registry
+type DjangoPbkdf2Decoder struct{}
+
+func NewDjangoPbkdf2Decoder() *DjangoPbkdf2Decoder {
+	return &DjangoPbkdf2Decoder{}
+}
+
+func (d *DjangoPbkdf2Decoder) Type() string {
+	return TypeDjangoPbkdf2
+}
+
+func (d *DjangoPbkdf2Decoder) DecodeValidator(eH string) ([]byte, password.Validator, error) {
+	dK, salt, p, err := DecodeDjangoPbkdf2(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v, err := NewDjangoPbkdf2(salt, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, v, nil
+}
+
+var _ password.Decoder = (*DjangoPbkdf2Decoder)(nil)
+
+// WerkzeugPbkdf2Decoder rebuilds validators from Werkzeug PBKDF2 hashes for use with a password.Registry
+type WerkzeugPbkdf2Decoder struct{}
+
+func NewWerkzeugPbkdf2Decoder() *WerkzeugPbkdf2Decoder {
+	return &WerkzeugPbkdf2Decoder{}
+}
+
+func (d *WerkzeugPbkdf2Decoder) Type() string {
+	return TypeWerkzeugPbkdf2
+}
+
+func (d *WerkzeugPbkdf2Decoder) DecodeValidator(eH string) ([]byte, password.Validator, error) {
+	dK, salt, p, err := DecodeWerkzeugPbkdf2(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v, err := NewWerkzeugPbkdf2(salt, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, v, nil
+}
+
+var _ password.Decoder = (*WerkzeugPbkdf2Decoder)(nil)
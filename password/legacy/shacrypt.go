@@ -0,0 +1,260 @@
+package legacy
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/theater-improrama/go-utils/password"
+)
+
+const (
+	TypeSHA256Crypt = "sha256-crypt"
+	TypeSHA512Crypt = "sha512-crypt"
+)
+
+const (
+	shaCryptRoundsPrefix  = "rounds="
+	shaCryptDefaultRounds = 5000
+	shaCryptMinRounds     = 1000
+	shaCryptMaxRounds     = 999999999
+	shaCryptMaxSaltLen    = 16
+)
+
+// shaCryptVariant describes the SHA-256 or SHA-512 variant of SHA-crypt
+type shaCryptVariant struct {
+	typ     string
+	id      string
+	hFn     func() hash.Hash
+	groups  [][]int
+	hashLen int
+}
+
+var (
+	sha256Crypt = shaCryptVariant{
+		typ: TypeSHA256Crypt,
+		id:  "5",
+		hFn: sha256.New,
+		groups: [][]int{
+			{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+			{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+			{31, 30},
+		},
+		hashLen: 43,
+	}
+	sha512Crypt = shaCryptVariant{
+		typ: TypeSHA512Crypt,
+		id:  "6",
+		hFn: sha512.New,
+		groups: [][]int{
+			{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4},
+			{47, 5, 26}, {6, 27, 48}, {28, 49, 7}, {50, 8, 29}, {9, 30, 51},
+			{31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13}, {56, 14, 35},
+			{15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19},
+			{62, 20, 41}, {63},
+		},
+		hashLen: 86,
+	}
+)
+
+type SHACryptParams struct {
+	Rounds int
+}
+
+// SHACryptValidator verifies SHA-crypt hashes ($5$ for SHA-256, $6$ for
+// SHA-512, optionally with rounds=<n>$ before the salt) as produced by
+// crypt(3) and openssl passwd -5/-6
+type SHACryptValidator struct {
+	verifyOnly
+	v      shaCryptVariant
+	salt   []byte
+	params SHACryptParams
+}
+
+func NewSHA256Crypt(salt []byte, p SHACryptParams) *SHACryptValidator {
+	return &SHACryptValidator{
+		v:      sha256Crypt,
+		salt:   salt,
+		params: p,
+	}
+}
+
+func NewSHA512Crypt(salt []byte, p SHACryptParams) *SHACryptValidator {
+	return &SHACryptValidator{
+		v:      sha512Crypt,
+		salt:   salt,
+		params: p,
+	}
+}
+
+// DecodeSHA256Crypt parses a SHA256-crypt hash and returns the encoded hash
+// part as derivative together with the salt and params
+func DecodeSHA256Crypt(eH string) ([]byte, []byte, SHACryptParams, error) {
+	return decodeSHACrypt(sha256Crypt, eH)
+}
+
+// DecodeSHA512Crypt parses a SHA512-crypt hash and returns the encoded hash
+// part as derivative together with the salt and params
+func DecodeSHA512Crypt(eH string) ([]byte, []byte, SHACryptParams, error) {
+	return decodeSHACrypt(sha512Crypt, eH)
+}
+
+func decodeSHACrypt(v shaCryptVariant, eH string) ([]byte, []byte, SHACryptParams, error) {
+	rest, ok := strings.CutPrefix(eH, "$"+v.id+"$")
+	if !ok {
+		return nil, nil, SHACryptParams{}, password.ErrUnexpectedType
+	}
+
+	p := SHACryptParams{
+		Rounds: shaCryptDefaultRounds,
+	}
+
+	if r, ok := strings.CutPrefix(rest, shaCryptRoundsPrefix); ok {
+		rs, rRest, ok := strings.Cut(r, "$")
+		if !ok {
+			return nil, nil, SHACryptParams{}, ErrInvalidHash
+		}
+
+		rounds, err := strconv.Atoi(rs)
+		if err != nil {
+			return nil, nil, SHACryptParams{}, ErrInvalidHash
+		}
+
+		p.Rounds = min(max(rounds, shaCryptMinRounds), shaCryptMaxRounds)
+		rest = rRest
+	}
+
+	salt, h, ok := strings.Cut(rest, "$")
+	if !ok || len(salt) > shaCryptMaxSaltLen || len(h) != v.hashLen {
+		return nil, nil, SHACryptParams{}, ErrInvalidHash
+	}
+
+	return []byte(h), []byte(salt), p, nil
+}
+
+func (v *SHACryptValidator) Type() string {
+	return v.v.typ
+}
+
+func (v *SHACryptValidator) Validate(dK []byte, password []byte) (bool, error) {
+	return equal(dK, shaCrypt(v.v, password, v.salt, v.params.Rounds)), nil
+}
+
+// repeat returns the first n bytes of b repeated
+func repeat(b []byte, n int) []byte {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		out = append(out, b[:min(len(b), n-len(out))]...)
+	}
+
+	return out
+}
+
+func shaCrypt(v shaCryptVariant, pw []byte, salt []byte, rounds int) []byte {
+	b := v.hFn()
+	b.Write(pw)
+	b.Write(salt)
+	b.Write(pw)
+	bSum := b.Sum(nil)
+
+	a := v.hFn()
+	a.Write(pw)
+	a.Write(salt)
+	a.Write(repeat(bSum, len(pw)))
+
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			a.Write(bSum)
+		} else {
+			a.Write(pw)
+		}
+	}
+
+	aSum := a.Sum(nil)
+
+	dp := v.hFn()
+	for range len(pw) {
+		dp.Write(pw)
+	}
+	p := repeat(dp.Sum(nil), len(pw))
+
+	ds := v.hFn()
+	for range 16 + int(aSum[0]) {
+		ds.Write(salt)
+	}
+	s := repeat(ds.Sum(nil), len(salt))
+
+	for r := range rounds {
+		c := v.hFn()
+
+		if r&1 != 0 {
+			c.Write(p)
+		} else {
+			c.Write(aSum)
+		}
+
+		if r%3 != 0 {
+			c.Write(s)
+		}
+
+		if r%7 != 0 {
+			c.Write(p)
+		}
+
+		if r&1 != 0 {
+			c.Write(aSum)
+		} else {
+			c.Write(p)
+		}
+
+		aSum = c.Sum(nil)
+	}
+
+	return cryptEncode(aSum, v.groups)
+}
+
+var _ password.Provider = (*SHACryptValidator)(nil)
+
+// SHACryptDecoder rebuilds validators from SHA256-crypt and SHA512-crypt
+// hashes for use with a password.Registry
+type SHACryptDecoder struct{}
+
+func NewSHACryptDecoder() *SHACryptDecoder {
+	return &SHACryptDecoder{}
+}
+
+func (d *SHACryptDecoder) Type() string {
+	return TypeSHA512Crypt
+}
+
+// Identifiers returns the SHA256-crypt type and the modular crypt prefixes of
+// SHA-crypt hashes
+func (d *SHACryptDecoder) Identifiers() []string {
+	return []string{TypeSHA256Crypt, sha256Crypt.id, sha512Crypt.id}
+}
+
+func (d *SHACryptDecoder) DecodeValidator(eH string) ([]byte, password.Validator, error) {
+	for _, v := range []shaCryptVariant{sha256Crypt, sha512Crypt} {
+		dK, salt, p, err := decodeSHACrypt(v, eH)
+		if err == password.ErrUnexpectedType {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return dK, &SHACryptValidator{
+			v:      v,
+			salt:   salt,
+			params: p,
+		}, nil
+	}
+
+	return nil, nil, password.ErrUnexpectedType
+}
+
+var _ password.Identifier = (*SHACryptDecoder)(nil)
+
+var _ password.Decoder = (*SHACryptDecoder)(nil)
@@ -0,0 +1,277 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// ErrInvalidLimit is returned for a non-positive concurrency limit
+var ErrInvalidLimit = errors.New("invalid limit")
+
+// MemoryEstimator is implemented by hashers and validators which can estimate
+// the memory in bytes a single hash operation allocates
+type MemoryEstimator interface {
+	EstimatedMemory() int64
+}
+
+// ContextValidator is implemented by validators honouring context
+// cancellation and deadlines
+type ContextValidator interface {
+	ValidateContext(ctx context.Context, dK []byte, password []byte) (bool, error)
+}
+
+// LimiterStats is a snapshot of a limiter's queue and usage
+type LimiterStats struct {
+	// Waiting is the number of operations waiting for a slot
+	Waiting int64
+	// Active is the number of operations currently hashing
+	Active int64
+	// ActiveMemory is the estimated memory in bytes of the active operations
+	ActiveMemory int64
+}
+
+// Limiter bounds the number and the estimated memory of concurrent hash
+// operations, protecting servers from running out of memory during login
+// floods. A limiter is usually shared by all providers of a process.
+type Limiter struct {
+	count     *semaphore.Weighted
+	memory    *semaphore.Weighted
+	maxMemory int64
+
+	waiting      atomic.Int64
+	active       atomic.Int64
+	activeMemory atomic.Int64
+}
+
+// NewLimiter returns a limiter allowing maxConcurrent operations using at most
+// maxMemory bytes in total. A maxMemory of 0 disables the memory limit.
+// Operations estimated to use more than maxMemory are limited to run alone.
+func NewLimiter(maxConcurrent int64, maxMemory int64) (*Limiter, error) {
+	if maxConcurrent <= 0 || maxMemory < 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	l := &Limiter{
+		count:     semaphore.NewWeighted(maxConcurrent),
+		maxMemory: maxMemory,
+	}
+
+	if maxMemory > 0 {
+		l.memory = semaphore.NewWeighted(maxMemory)
+	}
+
+	return l, nil
+}
+
+// Acquire waits for a slot for an operation estimated to use mem bytes. The
+// returned release function must be called once the operation finished.
+func (l *Limiter) Acquire(ctx context.Context, mem int64) (func(), error) {
+	l.waiting.Add(1)
+	defer l.waiting.Add(-1)
+
+	if err := l.count.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	if l.memory != nil {
+		mem = min(mem, l.maxMemory)
+
+		if err := l.memory.Acquire(ctx, mem); err != nil {
+			l.count.Release(1)
+			return nil, err
+		}
+	}
+
+	l.active.Add(1)
+	l.activeMemory.Add(mem)
+
+	return func() {
+		l.active.Add(-1)
+		l.activeMemory.Add(-mem)
+
+		if l.memory != nil {
+			l.memory.Release(mem)
+		}
+		l.count.Release(1)
+	}, nil
+}
+
+// Stats returns the current queue depth and usage
+func (l *Limiter) Stats() LimiterStats {
+	return LimiterStats{
+		Waiting:      l.waiting.Load(),
+		Active:       l.active.Load(),
+		ActiveMemory: l.activeMemory.Load(),
+	}
+}
+
+func estimatedMemory(v any) int64 {
+	e, ok := v.(MemoryEstimator)
+	if !ok {
+		return 0
+	}
+
+	return e.EstimatedMemory()
+}
+
+// LimitedProvider runs the operations of the wrapped provider within the
+// bounds of a Limiter. The context-free methods of Provider wait for a slot
+// without deadline, use the Context variants to bound waiting.
+type LimitedProvider struct {
+	p Provider
+	l *Limiter
+}
+
+// Limit returns providers running the providers returned by pFn within the
+// bounds of l
+func Limit(pFn ProviderFn, l *Limiter) ProviderFn {
+	return func() (Provider, error) {
+		p, err := pFn()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewLimitedProvider(p, l), nil
+	}
+}
+
+func NewLimitedProvider(p Provider, l *Limiter) *LimitedProvider {
+	return &LimitedProvider{
+		p: p,
+		l: l,
+	}
+}
+
+func (p *LimitedProvider) Type() string {
+	return p.p.Type()
+}
+
+func (p *LimitedProvider) HashEncodeContext(ctx context.Context, password []byte) (string, error) {
+	release, err := p.l.Acquire(ctx, estimatedMemory(p.p))
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	return p.p.HashEncode(password)
+}
+
+func (p *LimitedProvider) HashEncode(password []byte) (string, error) {
+	return p.HashEncodeContext(context.Background(), password)
+}
+
+func (p *LimitedProvider) HashContext(ctx context.Context, password []byte) ([]byte, error) {
+	release, err := p.l.Acquire(ctx, estimatedMemory(p.p))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return p.p.Hash(password)
+}
+
+func (p *LimitedProvider) Hash(password []byte) ([]byte, error) {
+	return p.HashContext(context.Background(), password)
+}
+
+func (p *LimitedProvider) ValidateContext(ctx context.Context, dK []byte, password []byte) (bool, error) {
+	return validateLimited(ctx, p.l, p.p, dK, password)
+}
+
+func (p *LimitedProvider) Validate(dK []byte, password []byte) (bool, error) {
+	return p.ValidateContext(context.Background(), dK, password)
+}
+
+// NeedsRehash delegates to the wrapped provider, it does not hash and is not limited
+func (p *LimitedProvider) NeedsRehash(eH string) (bool, error) {
+	c, ok := p.p.(RehashChecker)
+	if !ok {
+		return false, nil
+	}
+
+	return c.NeedsRehash(eH)
+}
+
+var _ ContextValidator = (*LimitedProvider)(nil)
+
+var _ RehashChecker = (*LimitedProvider)(nil)
+
+var _ Provider = (*LimitedProvider)(nil)
+
+func validateLimited(ctx context.Context, l *Limiter, v Validator, dK []byte, password []byte) (bool, error) {
+	release, err := l.Acquire(ctx, estimatedMemory(v))
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	return v.Validate(dK, password)
+}
+
+// LimitedDecoder wraps the validators of the wrapped decoder to run within
+// the bounds of a Limiter. Use Registry.VerifyContext to bound waiting.
+type LimitedDecoder struct {
+	d Decoder
+	l *Limiter
+}
+
+func NewLimitedDecoder(d Decoder, l *Limiter) *LimitedDecoder {
+	return &LimitedDecoder{
+		d: d,
+		l: l,
+	}
+}
+
+func (d *LimitedDecoder) Type() string {
+	return d.d.Type()
+}
+
+// Identifiers returns the identifiers of the wrapped decoder
+func (d *LimitedDecoder) Identifiers() []string {
+	i, ok := d.d.(Identifier)
+	if !ok {
+		return nil
+	}
+
+	return i.Identifiers()
+}
+
+func (d *LimitedDecoder) DecodeValidator(eH string) ([]byte, Validator, error) {
+	dK, v, err := d.d.DecodeValidator(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, &limitedValidator{
+		v: v,
+		l: d.l,
+	}, nil
+}
+
+var _ Identifier = (*LimitedDecoder)(nil)
+
+var _ Decoder = (*LimitedDecoder)(nil)
+
+type limitedValidator struct {
+	v Validator
+	l *Limiter
+}
+
+func (v *limitedValidator) Type() string {
+	return v.v.Type()
+}
+
+func (v *limitedValidator) ValidateContext(ctx context.Context, dK []byte, password []byte) (bool, error) {
+	return validateLimited(ctx, v.l, v.v, dK, password)
+}
+
+func (v *limitedValidator) Validate(dK []byte, password []byte) (bool, error) {
+	return v.ValidateContext(context.Background(), dK, password)
+}
+
+var _ ContextValidator = (*limitedValidator)(nil)
+
+var _ Validator = (*limitedValidator)(nil)
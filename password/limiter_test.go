@@ -0,0 +1,128 @@
+package password_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+)
+
+var _ = Describe("Limiter", func() {
+	It("should bound the number of concurrent operations", func() {
+		l, err := password.NewLimiter(1, 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		release, err := l.Acquire(context.Background(), 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(l.Stats()).To(Equal(password.LimiterStats{Active: 1}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = l.Acquire(ctx, 0)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+
+		release()
+
+		release, err = l.Acquire(context.Background(), 0)
+		Expect(err).ToNot(HaveOccurred())
+		release()
+	})
+
+	It("should bound the estimated memory of concurrent operations", func() {
+		l, err := password.NewLimiter(10, 100)
+		Expect(err).ToNot(HaveOccurred())
+
+		release, err := l.Acquire(context.Background(), 80)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(l.Stats()).To(Equal(password.LimiterStats{Active: 1, ActiveMemory: 80}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = l.Acquire(ctx, 30)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+
+		release()
+
+		// operations larger than the limit run alone instead of never
+		release, err = l.Acquire(context.Background(), 1000)
+		Expect(err).ToNot(HaveOccurred())
+		release()
+
+		Expect(l.Stats()).To(Equal(password.LimiterStats{}))
+	})
+
+	It("should report waiting operations", func() {
+		l, err := password.NewLimiter(1, 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		release, err := l.Acquire(context.Background(), 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+
+			r, err := l.Acquire(context.Background(), 0)
+			Expect(err).ToNot(HaveOccurred())
+			r()
+		}()
+
+		Eventually(func() int64 { return l.Stats().Waiting }).Should(Equal(int64(1)))
+
+		release()
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("should reject invalid limits", func() {
+		_, err := password.NewLimiter(0, 0)
+		Expect(err).To(MatchError(password.ErrInvalidLimit))
+	})
+
+	Describe("LimitedProvider", func() {
+		params := argon2id.Params{Time: 1, Memory: 64, Threads: 1, KeyLen: 32}
+
+		It("should hash and verify within the limits", func() {
+			l, err := password.NewLimiter(1, 64*1024)
+			Expect(err).ToNot(HaveOccurred())
+
+			p, err := password.Limit(argon2id.Provide(password.NewDefaultSaltGenerator(16), params), l)()
+			Expect(err).ToNot(HaveOccurred())
+
+			eH, err := p.HashEncode([]byte("password"))
+			Expect(err).ToNot(HaveOccurred())
+
+			r := password.NewRegistry(password.NewLimitedDecoder(argon2id.NewDecoder(), l))
+			Expect(r.VerifyContext(context.Background(), eH, []byte("password"))).To(BeTrue())
+		})
+
+		It("should honour context cancellation while waiting", func() {
+			l, err := password.NewLimiter(1, 0)
+			Expect(err).ToNot(HaveOccurred())
+
+			p := password.NewLimitedProvider(argon2id.New([]byte("somesalt"), params), l)
+
+			eH, err := p.HashEncode([]byte("password"))
+			Expect(err).ToNot(HaveOccurred())
+
+			release, err := l.Acquire(context.Background(), 0)
+			Expect(err).ToNot(HaveOccurred())
+			defer release()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err = p.HashEncodeContext(ctx, []byte("password"))
+			Expect(err).To(MatchError(context.Canceled))
+
+			r := password.NewRegistry(password.NewLimitedDecoder(argon2id.NewDecoder(), l))
+			_, err = r.VerifyContext(ctx, eH, []byte("password"))
+			Expect(err).To(MatchError(context.Canceled))
+		})
+	})
+})
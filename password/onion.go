@@ -0,0 +1,316 @@
+package password
+
+import "errors"
+
+// TypeOnion is the type of onion hashes, which wrap the derivative of an
+// inner hash in an outer hash, e.g. argon2id(pbkdf2(password)), so existing
+// hashes can be moved to a stronger algorithm without knowing the passwords.
+// They are encoded as PHC strings
+//
+//	$onion$<inner>$<outer>
+//
+// where <inner> is the base64 encoded inner hash with its derivative zeroed
+// and <outer> the base64 encoded outer hash. Inner hashes must be in the PHC
+// string format.
+const TypeOnion = "onion"
+
+// ErrNotHasher is returned when the inner hash of an onion hash cannot be
+// rebuilt into a hasher to replay the chain, e.g. for verify-only legacy and
+// bcrypt hashes
+var ErrNotHasher = errors.New("inner hash type cannot hash")
+
+// wrap returns the onion hash of the inner hash iEH wrapped by the outer hash oEH
+func wrap(iEH string, oEH string) (string, error) {
+	phc, err := ParsePHC(iEH)
+	if err != nil {
+		return "", err
+	}
+
+	// the derivative is not stored, but its length may be a param of the
+	// inner hash, so it is zeroed instead of removed
+	phc.Hash = make([]byte, len(phc.Hash))
+
+	o := PHC{
+		ID:   TypeOnion,
+		Salt: []byte(phc.String()),
+		Hash: []byte(oEH),
+	}
+
+	return o.String(), nil
+}
+
+// unwrap returns the inner hash, with zeroed derivative, and the outer hash of
+// the onion hash eH
+func unwrap(eH string) (string, string, error) {
+	phc, err := ParsePHC(eH)
+	if err != nil {
+		return "", "", err
+	}
+
+	if phc.ID != TypeOnion {
+		return "", "", ErrUnexpectedType
+	}
+
+	if phc.Salt == nil || phc.Hash == nil {
+		return "", "", ErrInvalidPHC
+	}
+
+	return string(phc.Salt), string(phc.Hash), nil
+}
+
+// OnionProvider hashes passwords with the inner provider and the resulting
+// derivative with the outer provider
+type OnionProvider struct {
+	inner Provider
+	outer Provider
+	r     *Registry
+}
+
+// Onion returns providers chaining the providers returned by inner and outer.
+// r must be able to decode the hashes encoded by the inner providers.
+func Onion(inner ProviderFn, outer ProviderFn, r *Registry) ProviderFn {
+	return func() (Provider, error) {
+		i, err := inner()
+		if err != nil {
+			return nil, err
+		}
+
+		o, err := outer()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewOnionProvider(i, o, r), nil
+	}
+}
+
+func NewOnionProvider(inner Provider, outer Provider, r *Registry) *OnionProvider {
+	return &OnionProvider{
+		inner: inner,
+		outer: outer,
+		r:     r,
+	}
+}
+
+func (p *OnionProvider) Type() string {
+	return TypeOnion
+}
+
+func (p *OnionProvider) HashEncode(password []byte) (string, error) {
+	iEH, err := p.inner.HashEncode(password)
+	if err != nil {
+		return "", err
+	}
+
+	dK, _, err := p.r.Decode(iEH)
+	if err != nil {
+		return "", err
+	}
+
+	oEH, err := p.outer.HashEncode(dK)
+	if err != nil {
+		return "", err
+	}
+
+	return wrap(iEH, oEH)
+}
+
+func (p *OnionProvider) Hash(password []byte) ([]byte, error) {
+	dK, err := p.inner.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.outer.Hash(dK)
+}
+
+// Validate replays the chain with the inner provider, so it fails with
+// ErrNoSalt for inner providers without a fixed salt. Validate encoded onion
+// hashes with an OnionDecoder.
+func (p *OnionProvider) Validate(dK []byte, password []byte) (bool, error) {
+	iDK, err := p.inner.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	return p.outer.Validate(dK, iDK)
+}
+
+var _ Provider = (*OnionProvider)(nil)
+
+// OnionDecoder rebuilds validators from onion hashes, decoding the inner and
+// outer hashes with a registry. Register it with the same registry to verify
+// onion hashes along with all other types.
+type OnionDecoder struct {
+	r *Registry
+}
+
+func NewOnionDecoder(r *Registry) *OnionDecoder {
+	return &OnionDecoder{
+		r: r,
+	}
+}
+
+func (d *OnionDecoder) Type() string {
+	return TypeOnion
+}
+
+func (d *OnionDecoder) DecodeValidator(eH string) ([]byte, Validator, error) {
+	iEH, oEH, err := unwrap(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, iV, err := d.r.Decode(iEH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iH, ok := iV.(Hasher)
+	if !ok {
+		return nil, nil, ErrNotHasher
+	}
+
+	dK, oV, err := d.r.Decode(oEH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, &onionValidator{
+		inner: iH,
+		outer: oV,
+	}, nil
+}
+
+var _ Decoder = (*OnionDecoder)(nil)
+
+// onionValidator validates by replaying the chain of an onion hash
+type onionValidator struct {
+	inner Hasher
+	outer Validator
+}
+
+func (v *onionValidator) Type() string {
+	return TypeOnion
+}
+
+func (v *onionValidator) Validate(dK []byte, password []byte) (bool, error) {
+	iDK, err := v.inner.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	return v.outer.Validate(dK, iDK)
+}
+
+// Hash replays the chain, so onion hashes may be nested
+func (v *onionValidator) Hash(password []byte) ([]byte, error) {
+	oH, ok := v.outer.(Hasher)
+	if !ok {
+		return nil, ErrNotHasher
+	}
+
+	iDK, err := v.inner.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return oH.Hash(iDK)
+}
+
+// Replays reports whether both hashes of the chain are replayable
+func (v *onionValidator) Replays() bool {
+	oR, ok := v.outer.(Replayer)
+	if !ok || !oR.Replays() {
+		return false
+	}
+
+	iR, ok := v.inner.(Replayer)
+
+	return ok && iR.Replays()
+}
+
+var _ Replayer = (*onionValidator)(nil)
+
+var _ Validator = (*onionValidator)(nil)
+
+// Upgrader wraps stored hashes in an outer hash without knowing the passwords
+type Upgrader struct {
+	r     *Registry
+	outer ProviderFn
+}
+
+// NewUpgrader returns an upgrader wrapping hashes decodable by r in hashes of
+// the providers returned by outer
+func NewUpgrader(r *Registry, outer ProviderFn) *Upgrader {
+	return &Upgrader{
+		r:     r,
+		outer: outer,
+	}
+}
+
+// Upgrade wraps the derivative of eH in an outer hash. Onion hashes and hashes
+// of the outer type are returned unchanged.
+func (u *Upgrader) Upgrade(eH string) (string, error) {
+	o, err := u.outer()
+	if err != nil {
+		return "", err
+	}
+
+	if IsPHC(eH) && (phcID(eH) == TypeOnion || identifies(o, phcID(eH))) {
+		return eH, nil
+	}
+
+	dK, v, err := u.r.Decode(eH)
+	if err != nil {
+		return "", err
+	}
+
+	// the chain is replayed on validation, so the inner hash must be rebuilt
+	// into a hasher reproducing the derivative
+	h, ok := v.(Replayer)
+	if !ok || !h.Replays() {
+		return "", ErrNotHasher
+	}
+
+	// inner hashes are stored as PHC strings, e.g. legacy JSON encoded hashes
+	// are re-encoded
+	if !IsPHC(eH) {
+		e, ok := v.(PHCEncoder)
+		if !ok {
+			return "", ErrInvalidPHC
+		}
+
+		eH, err = e.EncodePHC(dK)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	oEH, err := o.HashEncode(dK)
+	if err != nil {
+		return "", err
+	}
+
+	return wrap(eH, oEH)
+}
+
+// UpgradeBatch upgrades every hash of eHs. The upgraded hash and the error of
+// eHs[i] are returned at index i, failed hashes are returned unchanged.
+func (u *Upgrader) UpgradeBatch(eHs []string) ([]string, []error) {
+	res := make([]string, len(eHs))
+	errs := make([]error, len(eHs))
+
+	for i, eH := range eHs {
+		uEH, err := u.Upgrade(eH)
+		if err != nil {
+			res[i] = eH
+			errs[i] = err
+			continue
+		}
+
+		res[i] = uEH
+	}
+
+	return res, errs
+}
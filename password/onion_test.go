@@ -0,0 +1,156 @@
+package password_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/jsonext"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+	"github.com/theater-improrama/go-utils/password/bcrypt"
+	"github.com/theater-improrama/go-utils/password/legacy"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+var _ = Describe("Onion", func() {
+	sG := password.NewDefaultSaltGenerator(16)
+	inner := pbkdf2.Provide(sG, pbkdf2.Params{Iter: 1000, KeyLen: 32})
+	outer := argon2id.Provide(sG, argon2id.Params{Time: 1, Memory: 64, Threads: 1, KeyLen: 32})
+
+	var r *password.Registry
+
+	BeforeEach(func() {
+		r = password.NewRegistry(
+			argon2id.NewDecoder(),
+			pbkdf2.NewDecoder(),
+			bcrypt.NewDecoder(),
+			legacy.NewMD5CryptDecoder(),
+		)
+		r.Register(password.NewOnionDecoder(r))
+	})
+
+	hashEncode := func(pFn password.ProviderFn, pw string) string {
+		p, err := pFn()
+		Expect(err).ToNot(HaveOccurred())
+
+		eH, err := p.HashEncode([]byte(pw))
+		Expect(err).ToNot(HaveOccurred())
+
+		return eH
+	}
+
+	It("should upgrade stored hashes without the password", func() {
+		eH := hashEncode(inner, "password")
+
+		u := password.NewUpgrader(r, outer)
+
+		uEH, err := u.Upgrade(eH)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(uEH).To(HavePrefix("$onion$"))
+
+		// the inner derivative must not be stored
+		phc, err := password.ParsePHC(uEH)
+		Expect(err).ToNot(HaveOccurred())
+
+		iPHC, err := password.ParsePHC(string(phc.Salt))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(iPHC.Hash).To(Equal(make([]byte, 32)))
+
+		Expect(r.Verify(uEH, []byte("password"))).To(BeTrue())
+		Expect(r.Verify(uEH, []byte("wrong"))).To(BeFalse())
+	})
+
+	It("should upgrade hashes in the legacy JSON encoding", func() {
+		salt := []byte("saltsaltsaltsalt")
+		params := pbkdf2.Params{Iter: 1000, KeyLen: 32}
+
+		v, err := pbkdf2.New(salt, params)
+		Expect(err).ToNot(HaveOccurred())
+
+		dK, err := v.Hash([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+
+		// the base64 wrapped JSON encoding used before the PHC string format
+		bs, err := json.Marshal(&struct {
+			Derivative jsonext.Base64Arr `json:"derivative"`
+			Salt       jsonext.Base64Arr `json:"salt"`
+			Params     pbkdf2.Params     `json:"params"`
+		}{
+			Derivative: dK,
+			Salt:       salt,
+			Params:     params,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		legacy := base64.StdEncoding.EncodeToString(bs)
+		Expect(r.Verify(legacy, []byte("password"))).To(BeTrue())
+
+		uEH, err := password.NewUpgrader(r, outer).Upgrade(legacy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(uEH).To(HavePrefix("$onion$"))
+
+		Expect(r.Verify(uEH, []byte("password"))).To(BeTrue())
+		Expect(r.Verify(uEH, []byte("wrong"))).To(BeFalse())
+	})
+
+	It("should not upgrade hashes whose chain cannot be replayed", func() {
+		u := password.NewUpgrader(r, outer)
+
+		// verify-only legacy hash
+		_, err := u.Upgrade("$1$abcdefgh$G//4keteveJp0qb8z2DxG/")
+		Expect(err).To(MatchError(password.ErrNotHasher))
+
+		// bcrypt generates a new salt for every hash
+		_, err = u.Upgrade(hashEncode(bcrypt.Provide(bcrypt.TestParams()), "password"))
+		Expect(err).To(MatchError(password.ErrNotHasher))
+	})
+
+	It("should fail to hash and validate without a fixed salt", func() {
+		p, err := password.Onion(inner, outer, r)()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = p.Hash([]byte("password"))
+		Expect(err).To(MatchError(password.ErrNoSalt))
+
+		_, err = p.Validate([]byte("derivative"), []byte("password"))
+		Expect(err).To(MatchError(password.ErrNoSalt))
+	})
+
+	It("should hash new passwords through the chain", func() {
+		eH := hashEncode(password.Onion(inner, outer, r), "password")
+
+		Expect(r.Verify(eH, []byte("password"))).To(BeTrue())
+		Expect(r.Verify(eH, []byte("wrong"))).To(BeFalse())
+	})
+
+	It("should upgrade batches and skip upgraded hashes", func() {
+		u := password.NewUpgrader(r, outer)
+
+		onion, err := u.Upgrade(hashEncode(inner, "onion"))
+		Expect(err).ToNot(HaveOccurred())
+
+		current := hashEncode(outer, "current")
+
+		eHs := []string{hashEncode(inner, "password"), onion, current, "not a hash"}
+
+		uEHs, errs := u.UpgradeBatch(eHs)
+		Expect(errs[0]).ToNot(HaveOccurred())
+		Expect(errs[1]).ToNot(HaveOccurred())
+		Expect(errs[2]).ToNot(HaveOccurred())
+		Expect(errs[3]).To(MatchError(password.ErrUnknownType))
+
+		Expect(r.Verify(uEHs[0], []byte("password"))).To(BeTrue())
+		Expect(uEHs[1]).To(Equal(onion))
+		Expect(uEHs[2]).To(Equal(current))
+		Expect(uEHs[3]).To(Equal("not a hash"))
+	})
+
+	It("should flag onion hashes for rehashing", func() {
+		uEH, err := password.NewUpgrader(r, outer).Upgrade(hashEncode(inner, "password"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(password.NewRehashPolicy(outer).NeedsRehash(uEH)).To(BeTrue())
+	})
+})
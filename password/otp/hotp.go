@@ -0,0 +1,62 @@
+package otp
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// HOTP generates and verifies counter-based one-time passwords (RFC 4226)
+type HOTP struct {
+	cfg   Config
+	store CounterStore
+}
+
+func NewHOTP(cfg Config, store CounterStore) (*HOTP, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &HOTP{
+		cfg:   cfg,
+		store: store,
+	}, nil
+}
+
+// Generate returns the code of secret for counter
+func (h *HOTP) Generate(secret []byte, counter uint64) string {
+	return generate(secret, counter, h.cfg)
+}
+
+// Verify reports whether code is valid for secret for the counter following
+// the last used counter of id, looking ahead by the configured skew to
+// resynchronise with the client. The matching counter is recorded as used.
+func (h *HOTP) Verify(ctx context.Context, id string, secret []byte, code string) (bool, error) {
+	last, ok, err := h.store.Last(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	next := uint64(0)
+	if ok {
+		next = last + 1
+	}
+
+	for c := next; c <= next+h.cfg.Skew; c++ {
+		if !equal(generate(secret, c, h.cfg), code) {
+			continue
+		}
+
+		return h.store.Use(ctx, id, c)
+	}
+
+	return false, nil
+}
+
+// URI returns the otpauth URI to enrol secret in an authenticator app,
+// starting at counter
+func (h *HOTP) URI(issuer string, account string, secret []byte, counter uint64) string {
+	return uri("hotp", issuer, account, secret, h.cfg, url.Values{
+		"counter": {strconv.FormatUint(counter, 10)},
+	})
+}
@@ -0,0 +1,142 @@
+// Package otp implements HOTP (RFC 4226) and TOTP (RFC 6238) one-time
+// passwords for two-factor authentication
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/theater-improrama/go-utils/token"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmSHA1   Algorithm = "SHA1"
+	AlgorithmSHA256 Algorithm = "SHA256"
+	AlgorithmSHA512 Algorithm = "SHA512"
+)
+
+var algorithms = map[Algorithm]func() hash.Hash{
+	AlgorithmSHA1:   sha1.New,
+	AlgorithmSHA256: sha256.New,
+	AlgorithmSHA512: sha512.New,
+}
+
+// ErrInvalidConfig is returned for configs with unsupported digits, period or algorithm
+var ErrInvalidConfig = errors.New("invalid otp config")
+
+type Config struct {
+	// Digits is the length of the codes, 6 to 8
+	Digits int
+	// Period is the time step of TOTP codes
+	Period time.Duration
+	// Skew is the number of time steps before and after the current one TOTP
+	// codes are accepted for, and the number of counters HOTP codes are looked
+	// ahead for
+	Skew      uint64
+	Algorithm Algorithm
+}
+
+// DefaultConfig returns the config understood by all authenticator apps: 6
+// digits, 30 second period, SHA-1, and a skew of one step
+func DefaultConfig() Config {
+	return Config{
+		Digits:    6,
+		Period:    30 * time.Second,
+		Skew:      1,
+		Algorithm: AlgorithmSHA1,
+	}
+}
+
+func (c Config) validate() error {
+	if c.Digits < 6 || c.Digits > 8 {
+		return ErrInvalidConfig
+	}
+
+	if c.Period < time.Second {
+		return ErrInvalidConfig
+	}
+
+	if _, ok := algorithms[c.Algorithm]; !ok {
+		return ErrInvalidConfig
+	}
+
+	return nil
+}
+
+// secretEncoding is the encoding of secrets in otpauth URIs
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewSecret returns a random secret of the length recommended for the
+// algorithm (the output size of its hash function)
+func NewSecret(a Algorithm) ([]byte, error) {
+	hFn, ok := algorithms[a]
+	if !ok {
+		return nil, ErrInvalidConfig
+	}
+
+	return token.Default().Bytes(hFn().Size())
+}
+
+// generate returns the HOTP code of secret for counter
+func generate(secret []byte, counter uint64, c Config) string {
+	m := hmac.New(algorithms[c.Algorithm], secret)
+	_ = binary.Write(m, binary.BigEndian, counter)
+	sum := m.Sum(nil)
+
+	// dynamic truncation, RFC 4226 section 5.3
+	o := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[o:o+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range c.Digits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", c.Digits, bin%mod)
+}
+
+// equal compares codes in constant time
+func equal(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// uri builds an otpauth URI for enrolment via QR code
+func uri(typ string, issuer string, account string, secret []byte, c Config, extra url.Values) string {
+	label, rawLabel := account, url.PathEscape(account)
+	if issuer != "" {
+		label = issuer + ":" + label
+		rawLabel = url.PathEscape(issuer) + ":" + rawLabel
+	}
+
+	q := url.Values{}
+	q.Set("secret", secretEncoding.EncodeToString(secret))
+	q.Set("algorithm", string(c.Algorithm))
+	q.Set("digits", strconv.Itoa(c.Digits))
+	if issuer != "" {
+		q.Set("issuer", issuer)
+	}
+	for k, vs := range extra {
+		q[k] = vs
+	}
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     typ,
+		Path:     "/" + label,
+		RawPath:  "/" + rawLabel,
+		RawQuery: q.Encode(),
+	}).String()
+}
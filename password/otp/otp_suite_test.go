@@ -0,0 +1,13 @@
+package otp_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOtp(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Otp Suite")
+}
@@ -0,0 +1,138 @@
+package otp_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/otp"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+var _ = Describe("Otp", func() {
+	ctx := context.Background()
+	secret := []byte("12345678901234567890")
+
+	Describe("HOTP", func() {
+		It("should generate the RFC 4226 test vectors", func() {
+			h, err := otp.NewHOTP(otp.DefaultConfig(), otp.NewMemoryCounterStore())
+			Expect(err).ToNot(HaveOccurred())
+
+			for c, code := range []string{
+				"755224", "287082", "359152", "969429", "338314",
+				"254676", "287922", "162583", "399871", "520489",
+			} {
+				Expect(h.Generate(secret, uint64(c))).To(Equal(code))
+			}
+		})
+
+		It("should look ahead within the skew and reject used counters", func() {
+			h, err := otp.NewHOTP(otp.DefaultConfig(), otp.NewMemoryCounterStore())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(h.Verify(ctx, "alice", secret, "287082")).To(BeTrue())
+			Expect(h.Verify(ctx, "alice", secret, "287082")).To(BeFalse())
+			Expect(h.Verify(ctx, "alice", secret, "755224")).To(BeFalse())
+			Expect(h.Verify(ctx, "alice", secret, "969429")).To(BeTrue())
+			Expect(h.Verify(ctx, "alice", secret, "287922")).To(BeFalse())
+		})
+	})
+
+	Describe("TOTP", func() {
+		cfg := otp.DefaultConfig()
+		cfg.Digits = 8
+
+		DescribeTable("should generate the RFC 6238 test vectors",
+			func(a otp.Algorithm, seed string, unix int64, code string) {
+				c := cfg
+				c.Algorithm = a
+
+				t, err := otp.NewTOTP(c, otp.NewMemoryCounterStore())
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(t.Generate([]byte(seed), time.Unix(unix, 0))).To(Equal(code))
+			},
+			Entry("SHA1 at 59", otp.AlgorithmSHA1, "12345678901234567890", int64(59), "94287082"),
+			Entry("SHA256 at 59", otp.AlgorithmSHA256, "12345678901234567890123456789012", int64(59), "46119246"),
+			Entry("SHA512 at 59", otp.AlgorithmSHA512, strings.Repeat("1234567890", 6)+"1234", int64(59), "90693936"),
+			Entry("SHA1 at 1111111109", otp.AlgorithmSHA1, "12345678901234567890", int64(1111111109), "07081804"),
+			Entry("SHA256 at 1111111109", otp.AlgorithmSHA256, "12345678901234567890123456789012", int64(1111111109), "68084774"),
+			Entry("SHA512 at 1111111109", otp.AlgorithmSHA512, strings.Repeat("1234567890", 6)+"1234", int64(1111111109), "25091201"),
+		)
+
+		It("should accept codes within the skew only once", func() {
+			t, err := otp.NewTOTP(otp.DefaultConfig(), otp.NewMemoryCounterStore())
+			Expect(err).ToNot(HaveOccurred())
+
+			now := time.Unix(1111111109, 0)
+			prev := t.Generate(secret, now.Add(-30*time.Second))
+			cur := t.Generate(secret, now)
+
+			Expect(t.Verify(ctx, "alice", secret, t.Generate(secret, now.Add(-60*time.Second)), now)).To(BeFalse())
+			Expect(t.Verify(ctx, "alice", secret, prev, now)).To(BeTrue())
+			Expect(t.Verify(ctx, "alice", secret, prev, now)).To(BeFalse())
+			Expect(t.Verify(ctx, "bob", secret, prev, now)).To(BeTrue())
+			Expect(t.Verify(ctx, "alice", secret, cur, now)).To(BeTrue())
+			Expect(t.Verify(ctx, "alice", secret, prev, now)).To(BeFalse())
+		})
+
+		It("should reject invalid configs", func() {
+			c := otp.DefaultConfig()
+			c.Digits = 9
+			_, err := otp.NewTOTP(c, otp.NewMemoryCounterStore())
+			Expect(err).To(MatchError(otp.ErrInvalidConfig))
+
+			c = otp.DefaultConfig()
+			c.Algorithm = "MD5"
+			_, err = otp.NewTOTP(c, otp.NewMemoryCounterStore())
+			Expect(err).To(MatchError(otp.ErrInvalidConfig))
+		})
+
+		It("should build otpauth URIs", func() {
+			t, err := otp.NewTOTP(otp.DefaultConfig(), otp.NewMemoryCounterStore())
+			Expect(err).ToNot(HaveOccurred())
+
+			u, err := url.Parse(t.URI("Theater Improrama", "alice@example.com", secret))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(u.Scheme).To(Equal("otpauth"))
+			Expect(u.Host).To(Equal("totp"))
+			Expect(u.Path).To(Equal("/Theater Improrama:alice@example.com"))
+			Expect(u.Query()).To(Equal(url.Values{
+				"secret":    {"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"},
+				"issuer":    {"Theater Improrama"},
+				"algorithm": {"SHA1"},
+				"digits":    {"6"},
+				"period":    {"30"},
+			}))
+		})
+	})
+
+	It("should generate secrets sized for the algorithm", func() {
+		s, err := otp.NewSecret(otp.AlgorithmSHA256)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s).To(HaveLen(32))
+	})
+
+	It("should verify recovery codes hashed with a provider", func() {
+		pFn := pbkdf2.Provide(password.NewDefaultSaltGenerator(16), pbkdf2.Params{
+			KeyLen: 32,
+			Iter:   1,
+		})
+
+		codes, hashes, err := otp.NewRecoveryCodes(4, pFn)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(codes).To(HaveLen(4))
+		Expect(hashes).To(HaveLen(4))
+		Expect(codes[0]).To(MatchRegexp(`^[2-9A-HJ-NP-Z]{5}-[2-9A-HJ-NP-Z]{5}$`))
+
+		r := password.NewRegistry(pbkdf2.NewDecoder())
+
+		Expect(otp.VerifyRecoveryCode(r, strings.ToLower(codes[2]), hashes)).To(Equal(2))
+		Expect(otp.VerifyRecoveryCode(r, "AAAAA-AAAAA", hashes)).To(Equal(-1))
+	})
+})
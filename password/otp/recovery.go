@@ -0,0 +1,71 @@
+package otp
+
+import (
+	"strings"
+
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/token"
+)
+
+const (
+	// recoveryCodeLen is the number of characters of a recovery code, 50 bits of entropy
+	recoveryCodeLen = 10
+	// recoveryCodeGroup is the length of the dash separated groups of a recovery code
+	recoveryCodeGroup = 5
+)
+
+// NewRecoveryCodes returns n recovery codes (XXXXX-XXXXX) to be shown to the
+// user once, together with their hashes by the providers of pFn to be stored
+func NewRecoveryCodes(n int, pFn password.ProviderFn) ([]string, []string, error) {
+	p, err := pFn()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g := token.Default()
+
+	codes := make([]string, n)
+	hashes := make([]string, n)
+
+	for i := range n {
+		c, err := g.Code(recoveryCodeLen)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		eH, err := p.HashEncode([]byte(c))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		codes[i] = c[:recoveryCodeGroup] + "-" + c[recoveryCodeGroup:]
+		hashes[i] = eH
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode strips separators and case as users type codes
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// VerifyRecoveryCode returns the index of the hash in hashes matching code, or
+// -1 if none does. Remove the matching hash from the stored hashes, as each
+// recovery code may only be used once.
+func VerifyRecoveryCode(r *password.Registry, code string, hashes []string) (int, error) {
+	c := []byte(normalizeRecoveryCode(code))
+
+	for i, eH := range hashes {
+		ok, err := r.Verify(eH, c)
+		if err != nil {
+			return -1, err
+		}
+
+		if ok {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
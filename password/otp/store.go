@@ -0,0 +1,54 @@
+package otp
+
+import (
+	"context"
+	"sync"
+)
+
+// CounterStore records the last used counter per account to prevent codes
+// from being used twice. For TOTP the counter is the time step.
+type CounterStore interface {
+	// Last returns the last used counter of id and whether one was used yet
+	Last(ctx context.Context, id string) (uint64, bool, error)
+	// Use records counter as used for id if it is greater than the last used
+	// counter and reports whether it was. Implementations must perform the
+	// check and the update atomically.
+	Use(ctx context.Context, id string, counter uint64) (bool, error)
+}
+
+// MemoryCounterStore is a CounterStore keeping counters in memory, suited for
+// single instance deployments and tests
+type MemoryCounterStore struct {
+	mu   sync.Mutex
+	last map[string]uint64
+}
+
+func NewMemoryCounterStore() *MemoryCounterStore {
+	return &MemoryCounterStore{
+		last: make(map[string]uint64),
+	}
+}
+
+func (s *MemoryCounterStore) Last(_ context.Context, id string) (uint64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.last[id]
+
+	return l, ok, nil
+}
+
+func (s *MemoryCounterStore) Use(_ context.Context, id string, counter uint64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.last[id]; ok && counter <= l {
+		return false, nil
+	}
+
+	s.last[id] = counter
+
+	return true, nil
+}
+
+var _ CounterStore = (*MemoryCounterStore)(nil)
@@ -0,0 +1,58 @@
+package otp
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TOTP generates and verifies time-based one-time passwords (RFC 6238)
+type TOTP struct {
+	cfg   Config
+	store CounterStore
+}
+
+func NewTOTP(cfg Config, store CounterStore) (*TOTP, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &TOTP{
+		cfg:   cfg,
+		store: store,
+	}, nil
+}
+
+func (t *TOTP) step(at time.Time) uint64 {
+	return uint64(at.Unix()) / uint64(t.cfg.Period/time.Second)
+}
+
+// Generate returns the code of secret at the given time
+func (t *TOTP) Generate(secret []byte, at time.Time) string {
+	return generate(secret, t.step(at), t.cfg)
+}
+
+// Verify reports whether code is valid for secret at the given time, within
+// the configured skew. Accepted codes are recorded in the store for id, so
+// each code and any code of an earlier time step is rejected afterwards.
+func (t *TOTP) Verify(ctx context.Context, id string, secret []byte, code string, at time.Time) (bool, error) {
+	s := t.step(at)
+
+	for i := s - min(s, t.cfg.Skew); i <= s+t.cfg.Skew; i++ {
+		if !equal(generate(secret, i, t.cfg), code) {
+			continue
+		}
+
+		return t.store.Use(ctx, id, i)
+	}
+
+	return false, nil
+}
+
+// URI returns the otpauth URI to enrol secret in an authenticator app
+func (t *TOTP) URI(issuer string, account string, secret []byte) string {
+	return uri("totp", issuer, account, secret, t.cfg, url.Values{
+		"period": {strconv.Itoa(int(t.cfg.Period / time.Second))},
+	})
+}
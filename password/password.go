@@ -14,6 +14,19 @@ type Hasher interface {
 	Hash(password []byte) ([]byte, error)
 }
 
+// Replayer is implemented by hashers reproducing the derivative of the hash
+// they were decoded from, so they can be the inner hash of an onion hash
+type Replayer interface {
+	Hasher
+	Replays() bool
+}
+
+// PHCEncoder is implemented by validators encoding a derivative in the PHC
+// string format with the salt and params they were decoded with
+type PHCEncoder interface {
+	EncodePHC(dK []byte) (string, error)
+}
+
 type Validator interface {
 	Typer
 	Validate(dK []byte, password []byte) (bool, error)
@@ -26,6 +39,15 @@ type Provider interface {
 	Validator
 }
 
+// SaltGenerator generates salts. Implementations must be safe for concurrent
+// use, as providers generate a fresh salt for every encoded hash.
 type SaltGenerator interface {
 	Generate() ([]byte, error)
 }
+
+// SaltHasher hashes passwords with a caller-supplied salt
+type SaltHasher interface {
+	Typer
+	HashSalt(password []byte, salt []byte) ([]byte, error)
+	HashEncodeSalt(password []byte, salt []byte) (string, error)
+}
@@ -0,0 +1,152 @@
+// Package passwordtest provides a shared ginkgo test suite every
+// password.Provider implementation must pass.
+package passwordtest
+
+import (
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+)
+
+// DescribeProvider registers the shared provider specs for the providers
+// returned by pFn. d must decode the hashes encoded by these providers.
+//
+//	var _ = passwordtest.DescribeProvider("Argon2id", argon2id.Provide(sG, p), argon2id.NewDecoder())
+func DescribeProvider(name string, pFn password.ProviderFn, d password.Decoder) bool {
+	return Describe(name+" provider", func() {
+		var p password.Provider
+
+		BeforeEach(func() {
+			var err error
+
+			p, err = pFn()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		// decode returns the derivative and validator of an encoded hash of
+		// "password"
+		decode := func() ([]byte, password.Validator) {
+			eH, err := p.HashEncode([]byte("password"))
+			Expect(err).ToNot(HaveOccurred())
+
+			dK, v, err := d.DecodeValidator(eH)
+			Expect(err).ToNot(HaveOccurred())
+
+			return dK, v
+		}
+
+		It("should be identified by the decoder", func() {
+			ids := []string{d.Type()}
+			if i, ok := d.(password.Identifier); ok {
+				ids = append(ids, i.Identifiers()...)
+			}
+
+			Expect(ids).To(ContainElement(p.Type()))
+		})
+
+		It("should validate the password of its own hash", func() {
+			dK, err := p.Hash([]byte("password"))
+			if errors.Is(err, password.ErrNoSalt) {
+				_, err = p.Validate(dK, []byte("password"))
+				Expect(err).To(MatchError(password.ErrNoSalt))
+
+				dK, v := decode()
+				Expect(v.Validate(dK, []byte("password"))).To(BeTrue())
+
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(p.Validate(dK, []byte("password"))).To(BeTrue())
+		})
+
+		It("should round-trip encoded hashes through the decoder", func() {
+			eH, err := p.HashEncode([]byte("password"))
+			Expect(err).ToNot(HaveOccurred())
+
+			dK, v, err := d.DecodeValidator(eH)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(v.Type()).To(Equal(p.Type()))
+
+			Expect(v.Validate(dK, []byte("password"))).To(BeTrue())
+		})
+
+		It("should use a fresh salt for every encoded hash", func() {
+			eH1, err := p.HashEncode([]byte("password"))
+			Expect(err).ToNot(HaveOccurred())
+
+			eH2, err := p.HashEncode([]byte("password"))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(eH1).ToNot(Equal(eH2))
+		})
+
+		It("should not hash with a fixed salt", func() {
+			dK1, err := p.Hash([]byte("password"))
+			if errors.Is(err, password.ErrNoSalt) {
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+
+			dK2, err := p.Hash([]byte("password"))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(dK1).ToNot(Equal(dK2))
+		})
+
+		It("should be safe for concurrent use", func() {
+			const n = 8
+
+			var wg sync.WaitGroup
+			eHs := make([]string, n)
+
+			for i := range n {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					eH, err := p.HashEncode([]byte("password"))
+					Expect(err).ToNot(HaveOccurred())
+
+					eHs[i] = eH
+				}()
+			}
+			wg.Wait()
+
+			for _, eH := range eHs {
+				dK, v, err := d.DecodeValidator(eH)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(v.Validate(dK, []byte("password"))).To(BeTrue())
+			}
+		})
+
+		It("should not validate a wrong password", func() {
+			dK, v := decode()
+
+			Expect(v.Validate(dK, []byte("passwort"))).To(BeFalse())
+			Expect(v.Validate(dK, []byte(""))).To(BeFalse())
+		})
+
+		It("should not validate a truncated key", func() {
+			dK, v := decode()
+
+			for _, tK := range [][]byte{dK[:len(dK)-1], dK[:len(dK)/2], {}} {
+				ok, _ := v.Validate(tK, []byte("password"))
+				Expect(ok).To(BeFalse())
+			}
+		})
+
+		It("should not validate a key of a different length", func() {
+			dK, v := decode()
+
+			lK := append(append([]byte{}, dK...), dK...)
+
+			ok, _ := v.Validate(lK, []byte("password"))
+			Expect(ok).To(BeFalse())
+		})
+	})
+}
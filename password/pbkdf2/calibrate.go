@@ -0,0 +1,62 @@
+package pbkdf2
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// calibrationSaltLen is the salt length used while calibrating
+	calibrationSaltLen = 16
+	// minCalibrationDuration is the minimum duration a benchmark has to run
+	// for its result to be extrapolated
+	minCalibrationDuration = 10 * time.Millisecond
+)
+
+// ErrInvalidCalibration is returned for a non-positive target duration
+var ErrInvalidCalibration = errors.New("invalid calibration target")
+
+// Calibrate benchmarks PBKDF2 with the given digest (SHA-512 if empty) on the
+// current host and returns params hashing a password in at most
+// targetDuration. The key length is set to the digest's output size.
+func Calibrate(targetDuration time.Duration, digest string) (Params, error) {
+	if targetDuration <= 0 {
+		return Params{}, ErrInvalidCalibration
+	}
+
+	p, err := Params{Digest: digest}.withDigest()
+	if err != nil {
+		return Params{}, err
+	}
+
+	p.KeyLen = digests[p.Digest]().Size()
+	p.Iter = 1000
+
+	// iterations scale linearly, so a benchmark long enough to be measured
+	// accurately is extrapolated to the target
+	d := measure(p)
+	for d < minCalibrationDuration && d < targetDuration {
+		p.Iter *= 2
+		d = measure(p)
+	}
+
+	p.Iter = max(1, int(int64(p.Iter)*int64(targetDuration)/int64(max(d, 1))))
+
+	for p.Iter > 1 && measure(p) > targetDuration {
+		p.Iter = p.Iter * 9 / 10
+	}
+
+	return p, nil
+}
+
+// measure returns the time it takes to hash a password with p
+func measure(p Params) time.Duration {
+	salt := make([]byte, calibrationSaltLen)
+
+	s := time.Now()
+	pbkdf2.Key([]byte("calibration"), salt, p.Iter, p.KeyLen, digests[p.Digest])
+
+	return time.Since(s)
+}
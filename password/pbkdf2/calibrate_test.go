@@ -0,0 +1,36 @@
+package pbkdf2_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+var _ = Describe("Calibrate", func() {
+	It("should return params for the requested digest", func() {
+		p, err := pbkdf2.Calibrate(20*time.Millisecond, pbkdf2.DigestSha256)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(p.Iter).To(BeNumerically(">=", 1))
+		Expect(p.KeyLen).To(Equal(32))
+		Expect(p.Digest).To(Equal(pbkdf2.DigestSha256))
+	})
+
+	It("should default to SHA-512", func() {
+		p, err := pbkdf2.Calibrate(20*time.Millisecond, "")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(p.KeyLen).To(Equal(64))
+		Expect(p.Digest).To(Equal(pbkdf2.DigestSha512))
+	})
+
+	It("should reject invalid targets", func() {
+		_, err := pbkdf2.Calibrate(0, "")
+		Expect(err).To(MatchError(pbkdf2.ErrInvalidCalibration))
+
+		_, err = pbkdf2.Calibrate(time.Second, "md5")
+		Expect(err).To(MatchError(pbkdf2.ErrUnsupportedDigest))
+	})
+})
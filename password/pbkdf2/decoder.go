@@ -0,0 +1,38 @@
+package pbkdf2
+
+import "github.com/theater-improrama/go-utils/password"
+
+// Pbkdf2Decoder rebuilds validators from encoded hashes of every supported
+// digest for use with a password.Registry
+type Pbkdf2Decoder struct{}
+
+func NewDecoder() *Pbkdf2Decoder {
+	return &Pbkdf2Decoder{}
+}
+
+func (d *Pbkdf2Decoder) Type() string {
+	return TypeSha512
+}
+
+// Identifiers returns the types of the other supported digests
+func (d *Pbkdf2Decoder) Identifiers() []string {
+	return []string{TypeSha256, TypeSha1}
+}
+
+func (d *Pbkdf2Decoder) DecodeValidator(eH string) ([]byte, password.Validator, error) {
+	dK, salt, p, err := Decode(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v, err := New(salt, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, v, nil
+}
+
+var _ password.Identifier = (*Pbkdf2Decoder)(nil)
+
+var _ password.Decoder = (*Pbkdf2Decoder)(nil)
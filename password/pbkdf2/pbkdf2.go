@@ -1,42 +1,100 @@
 package pbkdf2
 
 import (
-	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/sha512"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"hash"
+	"strconv"
+	"strings"
 
 	jsonext "github.com/theater-improrama/go-utils/jsonext"
 	"github.com/theater-improrama/go-utils/password"
 	"golang.org/x/crypto/pbkdf2"
 )
 
+// Provide returns providers hashing with the digest of p, SHA-512 if unset.
+// The providers generate a fresh salt with sG for every encoded hash, so a
+// single provider may be reused and shared between goroutines. Hash and
+// Validate fail with password.ErrNoSalt.
 func Provide(sG password.SaltGenerator, p Params) password.ProviderFn {
 	return func() (password.Provider, error) {
-		s, err := sG.Generate()
+		v, err := New(
+			nil,
+			p,
+		)
 		if err != nil {
 			return nil, err
 		}
+		v.sG = sG
 
-		return NewSha512(
-			s,
-			p,
-		), nil
+		return v, nil
 	}
 }
 
-const TypeSha512 = "pbkdf2-sha512"
+const (
+	DigestSha1   = "sha1"
+	DigestSha256 = "sha256"
+	DigestSha512 = "sha512"
+)
+
+const (
+	typePrefix = "pbkdf2-"
+
+	// TypeSha1 is only meant for verifying hashes imported from legacy systems
+	TypeSha1   = typePrefix + DigestSha1
+	TypeSha256 = typePrefix + DigestSha256
+	TypeSha512 = typePrefix + DigestSha512
+)
+
+// ErrUnsupportedDigest is returned for digests other than DigestSha1, DigestSha256 and DigestSha512
+var ErrUnsupportedDigest = errors.New("unsupported pbkdf2 digest")
 
-type Pbkdf2Sha512HasherValidator struct {
+var digests = map[string]func() hash.Hash{
+	DigestSha1:   sha1.New,
+	DigestSha256: sha256.New,
+	DigestSha512: sha512.New,
+}
+
+type Pbkdf2HasherValidator struct {
+	sG     password.SaltGenerator
 	hFn    func() hash.Hash
 	salt   []byte
 	params Params
 }
 
+// Pbkdf2Sha512HasherValidator is the PBKDF2 hasher validator.
+//
+// Deprecated: Use Pbkdf2HasherValidator, which supports all digests.
+type Pbkdf2Sha512HasherValidator = Pbkdf2HasherValidator
+
 type Params struct {
 	KeyLen int `json:"key_len"`
 	Iter   int `json:"iter"`
+	// Digest is the hash function used for HMAC, DigestSha512 if unset
+	Digest string `json:"digest,omitempty"`
+}
+
+// TestParams returns a single SHA-512 iteration, for tests only
+func TestParams() Params {
+	return Params{KeyLen: 32, Iter: 1, Digest: DigestSha512}
+}
+
+// withDigest returns p with the digest defaulted to DigestSha512
+func (p Params) withDigest() (Params, error) {
+	if p.Digest == "" {
+		p.Digest = DigestSha512
+	}
+
+	if _, ok := digests[p.Digest]; !ok {
+		return Params{}, ErrUnsupportedDigest
+	}
+
+	return p, nil
 }
 
 type encodableHash struct {
@@ -45,7 +103,46 @@ type encodableHash struct {
 	Params     Params            `json:"params"`
 }
 
+// Decode parses a PBKDF2 hash in the PHC string format
+// ($pbkdf2-<digest>$i=<iter>$<salt>$<hash>). Hashes in the legacy base64
+// wrapped JSON encoding are accepted as well. The digest of the returned
+// params is always set.
 func Decode(eH string) ([]byte, []byte, Params, error) {
+	if !password.IsPHC(eH) {
+		return decodeLegacy(eH)
+	}
+
+	phc, err := password.ParsePHC(eH)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	d, ok := strings.CutPrefix(phc.ID, typePrefix)
+	if !ok {
+		return nil, nil, Params{}, password.ErrUnexpectedType
+	}
+
+	if _, ok := digests[d]; !ok {
+		return nil, nil, Params{}, ErrUnsupportedDigest
+	}
+
+	i, err := phc.UintParam("i", 31)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	if i < 1 || len(phc.Salt) == 0 || len(phc.Hash) == 0 {
+		return nil, nil, Params{}, password.ErrInvalidPHC
+	}
+
+	return phc.Hash, phc.Salt, Params{
+		KeyLen: len(phc.Hash),
+		Iter:   int(i),
+		Digest: d,
+	}, nil
+}
+
+func decodeLegacy(eH string) ([]byte, []byte, Params, error) {
 	bs, err := base64.StdEncoding.DecodeString(eH)
 	if err != nil {
 		return nil, nil, Params{}, err
@@ -56,49 +153,127 @@ func Decode(eH string) ([]byte, []byte, Params, error) {
 		return nil, nil, Params{}, err
 	}
 
-	return h.Derivative, h.Salt, h.Params, nil
+	// the legacy encoding does not record the hash type, so hashes of other
+	// algorithms are told apart by their missing params
+	if h.Params.Iter == 0 {
+		return nil, nil, Params{}, password.ErrUnexpectedType
+	}
+
+	p, err := h.Params.withDigest()
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	return h.Derivative, h.Salt, p, nil
 }
 
+// Encode encodes a PBKDF2 hash in the PHC string format. The key length is
+// implied by the length of the derivative.
 func Encode(dK []byte, salt []byte, p Params) (string, error) {
-	eH := encodableHash{
-		Derivative: dK,
-		Salt:       salt,
-		Params:     p,
+	p, err := p.withDigest()
+	if err != nil {
+		return "", err
+	}
+
+	phc := password.PHC{
+		ID: typePrefix + p.Digest,
+		Params: []password.PHCParam{
+			{Name: "i", Value: strconv.Itoa(p.Iter)},
+		},
+		Salt: salt,
+		Hash: dK,
 	}
 
-	s, err := json.Marshal(&eH)
+	return phc.String(), nil
+}
+
+// New returns a hasher validator using the digest of p, SHA-512 if unset
+func New(
+	salt []byte,
+	p Params,
+) (*Pbkdf2HasherValidator, error) {
+	p, err := p.withDigest()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return base64.StdEncoding.EncodeToString(s), nil
+	return &Pbkdf2HasherValidator{
+		hFn:    digests[p.Digest],
+		salt:   salt,
+		params: p,
+	}, nil
 }
 
-func NewSha512(
+func newDigest(
+	d string,
 	salt []byte,
 	p Params,
-) *Pbkdf2Sha512HasherValidator {
-	return &Pbkdf2Sha512HasherValidator{
-		hFn:    sha512.New,
+) *Pbkdf2HasherValidator {
+	p.Digest = d
+
+	return &Pbkdf2HasherValidator{
+		hFn:    digests[d],
 		salt:   salt,
 		params: p,
 	}
 }
 
-func (p *Pbkdf2Sha512HasherValidator) Type() string {
-	return TypeSha512
+// NewSha1 returns a PBKDF2-SHA1 hasher validator, which is only meant for
+// verifying hashes imported from legacy systems
+func NewSha1(
+	salt []byte,
+	p Params,
+) *Pbkdf2HasherValidator {
+	return newDigest(DigestSha1, salt, p)
+}
+
+func NewSha256(
+	salt []byte,
+	p Params,
+) *Pbkdf2HasherValidator {
+	return newDigest(DigestSha256, salt, p)
 }
 
-func (p *Pbkdf2Sha512HasherValidator) HashEncode(password []byte) (string, error) {
-	dK, err := p.Hash(password)
+func NewSha512(
+	salt []byte,
+	p Params,
+) *Pbkdf2HasherValidator {
+	return newDigest(DigestSha512, salt, p)
+}
+
+func (p *Pbkdf2HasherValidator) Type() string {
+	return typePrefix + p.params.Digest
+}
+
+// HashEncode hashes password with a fresh salt if sG is set, the fixed salt
+// otherwise
+func (p *Pbkdf2HasherValidator) HashEncode(password []byte) (string, error) {
+	salt := p.salt
+
+	if p.sG != nil {
+		s, err := p.sG.Generate()
+		if err != nil {
+			return "", err
+		}
+
+		salt = s
+	}
+
+	return p.HashEncodeSalt(password, salt)
+}
+
+// HashEncodeSalt hashes password with the caller-supplied salt and encodes the hash
+func (p *Pbkdf2HasherValidator) HashEncodeSalt(password []byte, salt []byte) (string, error) {
+	dK, err := p.HashSalt(password, salt)
 	if err != nil {
 		return "", err
 	}
 
-	return Encode(dK, p.salt, p.params)
+	return Encode(dK, salt, p.params)
 }
 
-func (p *Pbkdf2Sha512HasherValidator) hash(password []byte, salt []byte) ([]byte, error) {
+// HashSalt hashes password with the caller-supplied salt
+func (p *Pbkdf2HasherValidator) HashSalt(password []byte, salt []byte) ([]byte, error) {
 	dK := pbkdf2.Key(
 		password,
 		salt,
@@ -110,21 +285,61 @@ func (p *Pbkdf2Sha512HasherValidator) hash(password []byte, salt []byte) ([]byte
 	return dK, nil
 }
 
-func (p *Pbkdf2Sha512HasherValidator) Hash(password []byte) ([]byte, error) {
-	return p.hash(password, p.salt)
+// Hash hashes pw with the fixed salt
+func (p *Pbkdf2HasherValidator) Hash(pw []byte) ([]byte, error) {
+	if len(p.salt) == 0 {
+		return nil, password.ErrNoSalt
+	}
+
+	return p.HashSalt(pw, p.salt)
 }
 
-func (p *Pbkdf2Sha512HasherValidator) Validate(dk []byte, password []byte) (bool, error) {
+func (p *Pbkdf2HasherValidator) Validate(dk []byte, password []byte) (bool, error) {
 	pDk, err := p.Hash(password)
 	if err != nil {
 		return false, err
 	}
 
-	if bytes.Compare(dk, pDk) != 0 {
+	kL := int32(len(dk))
+	pKL := int32(len(pDk))
+
+	if subtle.ConstantTimeEq(kL, pKL) == 0 {
+		return false, nil
+	}
+
+	if subtle.ConstantTimeCompare(dk, pDk) != 1 {
 		return false, nil
 	}
 
 	return true, nil
 }
 
-var _ password.Provider = (*Pbkdf2Sha512HasherValidator)(nil)
+// NeedsRehash reports whether eH was produced with params differing from p's
+func (p *Pbkdf2HasherValidator) NeedsRehash(eH string) (bool, error) {
+	_, _, hP, err := Decode(eH)
+	if err != nil {
+		return false, err
+	}
+
+	return hP != p.params, nil
+}
+
+var _ password.RehashChecker = (*Pbkdf2HasherValidator)(nil)
+
+// Replays reports whether p hashes with a fixed salt
+func (p *Pbkdf2HasherValidator) Replays() bool {
+	return len(p.salt) > 0
+}
+
+var _ password.Replayer = (*Pbkdf2HasherValidator)(nil)
+
+// EncodePHC encodes dK with the salt and params of p
+func (p *Pbkdf2HasherValidator) EncodePHC(dK []byte) (string, error) {
+	return Encode(dK, p.salt, p.params)
+}
+
+var _ password.PHCEncoder = (*Pbkdf2HasherValidator)(nil)
+
+var _ password.SaltHasher = (*Pbkdf2HasherValidator)(nil)
+
+var _ password.Provider = (*Pbkdf2HasherValidator)(nil)
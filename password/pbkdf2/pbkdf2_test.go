@@ -0,0 +1,127 @@
+package pbkdf2_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/jsonext"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+// encodeLegacy reproduces the base64 wrapped JSON encoding used before the
+// PHC string format
+func encodeLegacy(dK []byte, salt []byte, p pbkdf2.Params) string {
+	bs, err := json.Marshal(&struct {
+		Derivative jsonext.Base64Arr `json:"derivative"`
+		Salt       jsonext.Base64Arr `json:"salt"`
+		Params     pbkdf2.Params     `json:"params"`
+	}{
+		Derivative: dK,
+		Salt:       salt,
+		Params:     p,
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	return base64.StdEncoding.EncodeToString(bs)
+}
+
+var _ = Describe("Pbkdf2", func() {
+	params := pbkdf2.Params{
+		KeyLen: 32,
+		Iter:   1000,
+		Digest: pbkdf2.DigestSha512,
+	}
+
+	It("should encode hashes in the PHC string format", func() {
+		v := pbkdf2.NewSha512([]byte("saltsaltsaltsalt"), params)
+
+		eH, err := v.HashEncode([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(eH).To(Equal("$pbkdf2-sha512$i=1000$c2FsdHNhbHRzYWx0c2FsdA$715rqIr5dXOVPpBhqqsugl037zT5bWJTWYmZtIcK8hA"))
+	})
+
+	It("should decode hashes produced by other implementations", func() {
+		dK, salt, p, err := pbkdf2.Decode("$pbkdf2-sha512$i=1000$c2FsdHNhbHRzYWx0c2FsdA$715rqIr5dXOVPpBhqqsugl037zT5bWJTWYmZtIcK8hA")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(salt).To(Equal([]byte("saltsaltsaltsalt")))
+		Expect(p).To(Equal(params))
+
+		Expect(pbkdf2.NewSha512(salt, p).Validate(dK, []byte("password"))).To(BeTrue())
+	})
+
+	It("should decode hashes in the legacy JSON encoding", func() {
+		v := pbkdf2.NewSha512([]byte("saltsaltsaltsalt"), params)
+
+		lDk, err := v.Hash([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+
+		legacy := encodeLegacy(lDk, []byte("saltsaltsaltsalt"), params)
+
+		dK, salt, p, err := pbkdf2.Decode(legacy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(salt).To(Equal([]byte("saltsaltsaltsalt")))
+		Expect(p).To(Equal(params))
+
+		Expect(pbkdf2.NewSha512(salt, p).Validate(dK, []byte("password"))).To(BeTrue())
+	})
+
+	It("should reject hashes of other algorithms", func() {
+		_, _, _, err := pbkdf2.Decode("$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$c29tZWhhc2g")
+		Expect(err).To(MatchError(password.ErrUnexpectedType))
+	})
+
+	DescribeTable("should record the digest in the encoded hash",
+		func(v *pbkdf2.Pbkdf2HasherValidator, eH string) {
+			Expect(v.HashEncode([]byte("password"))).To(Equal(eH))
+
+			dK, salt, p, err := pbkdf2.Decode(eH)
+			Expect(err).ToNot(HaveOccurred())
+
+			dV, err := pbkdf2.New(salt, p)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dV.Type()).To(Equal(v.Type()))
+			Expect(dV.Validate(dK, []byte("password"))).To(BeTrue())
+		},
+		Entry("SHA-1",
+			pbkdf2.NewSha1([]byte("saltsaltsaltsalt"), params),
+			"$pbkdf2-sha1$i=1000$c2FsdHNhbHRzYWx0c2FsdA$2FWw/oC7TQkskizC+81lWlmFAMPzfuUU9jSdPALS95I",
+		),
+		Entry("SHA-256",
+			pbkdf2.NewSha256([]byte("saltsaltsaltsalt"), params),
+			"$pbkdf2-sha256$i=1000$c2FsdHNhbHRzYWx0c2FsdA$8nX7hwFEzIB8aPajJTYK8weHQc5Ngz0pFVAKvSu4jQA",
+		),
+		Entry("SHA-512",
+			pbkdf2.NewSha512([]byte("saltsaltsaltsalt"), params),
+			"$pbkdf2-sha512$i=1000$c2FsdHNhbHRzYWx0c2FsdA$715rqIr5dXOVPpBhqqsugl037zT5bWJTWYmZtIcK8hA",
+		),
+	)
+
+	It("should default to SHA-512", func() {
+		v, err := pbkdf2.New([]byte("saltsaltsaltsalt"), pbkdf2.Params{KeyLen: 32, Iter: 1000})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(v.Type()).To(Equal(pbkdf2.TypeSha512))
+	})
+
+	It("should reject unsupported digests", func() {
+		_, err := pbkdf2.New([]byte("saltsaltsaltsalt"), pbkdf2.Params{KeyLen: 32, Iter: 1000, Digest: "md5"})
+		Expect(err).To(MatchError(pbkdf2.ErrUnsupportedDigest))
+
+		_, _, _, err = pbkdf2.Decode("$pbkdf2-md5$i=1000$c2FsdHNhbHRzYWx0c2FsdA$aGFzaA")
+		Expect(err).To(MatchError(pbkdf2.ErrUnsupportedDigest))
+	})
+
+	DescribeTable("should reject malformed PHC strings",
+		func(eH string) {
+			_, _, _, err := pbkdf2.Decode(eH)
+			Expect(err).To(MatchError(password.ErrInvalidPHC))
+		},
+		Entry("missing hash", "$pbkdf2-sha512$i=1000$c2FsdA"),
+		Entry("empty hash", "$pbkdf2-sha512$i=1000$c2FsdA$"),
+		Entry("missing salt", "$pbkdf2-sha512$i=1000"),
+		Entry("empty salt", "$pbkdf2-sha512$i=1000$$aGFzaA"),
+		Entry("zero iterations", "$pbkdf2-sha512$i=0$c2FsdA$aGFzaA"),
+	)
+})
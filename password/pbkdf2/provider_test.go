@@ -0,0 +1,34 @@
+package pbkdf2_test
+
+import (
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/passwordtest"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+var _ = passwordtest.DescribeProvider(
+	"Pbkdf2Sha512",
+	pbkdf2.Provide(
+		password.NewDefaultSaltGenerator(16),
+		pbkdf2.Params{Iter: 1000, KeyLen: 32},
+	),
+	pbkdf2.NewDecoder(),
+)
+
+var _ = passwordtest.DescribeProvider(
+	"Pbkdf2Sha256",
+	pbkdf2.Provide(
+		password.NewDefaultSaltGenerator(16),
+		pbkdf2.Params{Iter: 1000, KeyLen: 32, Digest: pbkdf2.DigestSha256},
+	),
+	pbkdf2.NewDecoder(),
+)
+
+var _ = passwordtest.DescribeProvider(
+	"Pbkdf2Sha1",
+	pbkdf2.Provide(
+		password.NewDefaultSaltGenerator(16),
+		pbkdf2.Params{Iter: 1000, KeyLen: 20, Digest: pbkdf2.DigestSha1},
+	),
+	pbkdf2.NewDecoder(),
+)
@@ -0,0 +1,261 @@
+package password
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"slices"
+)
+
+// pepperKeyIDParam is the PHC param recording the ID of the pepper key, as
+// defined for argon2 by the PHC string format specification
+const pepperKeyIDParam = "keyid"
+
+// pepper mixes the secret key into password using HMAC-SHA256
+func pepper(key []byte, password []byte) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write(password)
+
+	return m.Sum(nil)
+}
+
+// withoutKeyID returns eH without its pepper key ID param and the ID, which
+// is empty if eH was not peppered
+func withoutKeyID(eH string) (string, string, error) {
+	phc, err := ParsePHC(eH)
+	if err != nil {
+		return "", "", err
+	}
+
+	id, ok := phc.Param(pepperKeyIDParam)
+	if !ok {
+		return eH, "", nil
+	}
+
+	phc.Params = slices.DeleteFunc(slices.Clone(phc.Params), func(p PHCParam) bool {
+		return p.Name == pepperKeyIDParam
+	})
+
+	return phc.String(), id, nil
+}
+
+// withKeyID returns eH with the pepper key ID param appended
+func withKeyID(eH string, id string) (string, error) {
+	phc, err := ParsePHC(eH)
+	if err != nil {
+		return "", err
+	}
+
+	phc.Params = append(phc.Params, PHCParam{
+		Name:  pepperKeyIDParam,
+		Value: id,
+	})
+
+	return phc.String(), nil
+}
+
+// PepperedProvider mixes a secret pepper held outside the database into every
+// password before hashing it with the wrapped provider. Encoded hashes record
+// the ID of the pepper key in the keyid PHC param, so the wrapped provider must
+// encode hashes in the PHC string format.
+type PepperedProvider struct {
+	p  Provider
+	kr *Keyring
+}
+
+// Pepper returns providers peppering passwords with the current key of kr
+// before hashing them with the providers returned by pFn
+func Pepper(pFn ProviderFn, kr *Keyring) ProviderFn {
+	return func() (Provider, error) {
+		p, err := pFn()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewPepperedProvider(p, kr), nil
+	}
+}
+
+func NewPepperedProvider(p Provider, kr *Keyring) *PepperedProvider {
+	return &PepperedProvider{
+		p:  p,
+		kr: kr,
+	}
+}
+
+func (p *PepperedProvider) Type() string {
+	return p.p.Type()
+}
+
+func (p *PepperedProvider) HashEncode(password []byte) (string, error) {
+	id, key := p.kr.Current()
+
+	eH, err := p.p.HashEncode(pepper(key, password))
+	if err != nil {
+		return "", err
+	}
+
+	return withKeyID(eH, id)
+}
+
+// Hash hashes password peppered with the current key
+func (p *PepperedProvider) Hash(password []byte) ([]byte, error) {
+	_, key := p.kr.Current()
+
+	return p.p.Hash(pepper(key, password))
+}
+
+// EstimatedMemory returns the estimate of the wrapped provider
+func (p *PepperedProvider) EstimatedMemory() int64 {
+	return estimatedMemory(p.p)
+}
+
+// Validate validates password peppered with the current key. Use a
+// PepperedDecoder to validate encoded hashes peppered with retired keys.
+func (p *PepperedProvider) Validate(dK []byte, password []byte) (bool, error) {
+	_, key := p.kr.Current()
+
+	return p.p.Validate(dK, pepper(key, password))
+}
+
+// NeedsRehash reports whether eH was not peppered, was peppered with a retired
+// key, or needs to be rehashed according to the wrapped provider
+func (p *PepperedProvider) NeedsRehash(eH string) (bool, error) {
+	uH, id, err := withoutKeyID(eH)
+	if err != nil {
+		return false, err
+	}
+
+	if id == "" || p.kr.IsRetired(id) {
+		return true, nil
+	}
+
+	c, ok := p.p.(RehashChecker)
+	if !ok {
+		return false, nil
+	}
+
+	return c.NeedsRehash(uH)
+}
+
+var _ MemoryEstimator = (*PepperedProvider)(nil)
+
+var _ RehashChecker = (*PepperedProvider)(nil)
+
+var _ Provider = (*PepperedProvider)(nil)
+
+// PepperedDecoder rebuilds validators from encoded hashes peppered by a
+// PepperedProvider, looking up the pepper key by the ID recorded in the hash.
+// Hashes without pepper key ID are passed to the wrapped decoder as is.
+type PepperedDecoder struct {
+	d  Decoder
+	kr *Keyring
+}
+
+func NewPepperedDecoder(d Decoder, kr *Keyring) *PepperedDecoder {
+	return &PepperedDecoder{
+		d:  d,
+		kr: kr,
+	}
+}
+
+func (d *PepperedDecoder) Type() string {
+	return d.d.Type()
+}
+
+// Identifiers returns the identifiers of the wrapped decoder
+func (d *PepperedDecoder) Identifiers() []string {
+	i, ok := d.d.(Identifier)
+	if !ok {
+		return nil
+	}
+
+	return i.Identifiers()
+}
+
+func (d *PepperedDecoder) DecodeValidator(eH string) ([]byte, Validator, error) {
+	if !IsPHC(eH) {
+		return d.d.DecodeValidator(eH)
+	}
+
+	uH, id, err := withoutKeyID(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if id == "" {
+		return d.d.DecodeValidator(eH)
+	}
+
+	key, err := d.kr.Key(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dK, v, err := d.d.DecodeValidator(uH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, &pepperedValidator{
+		v:   v,
+		key: key,
+	}, nil
+}
+
+var _ Identifier = (*PepperedDecoder)(nil)
+
+var _ Decoder = (*PepperedDecoder)(nil)
+
+type pepperedValidator struct {
+	v   Validator
+	key []byte
+}
+
+func (v *pepperedValidator) Type() string {
+	return v.v.Type()
+}
+
+func (v *pepperedValidator) Validate(dK []byte, password []byte) (bool, error) {
+	return v.v.Validate(dK, pepper(v.key, password))
+}
+
+func (v *pepperedValidator) ValidateContext(ctx context.Context, dK []byte, password []byte) (bool, error) {
+	cV, ok := v.v.(ContextValidator)
+	if !ok {
+		return v.Validate(dK, password)
+	}
+
+	return cV.ValidateContext(ctx, dK, pepper(v.key, password))
+}
+
+var _ ContextValidator = (*pepperedValidator)(nil)
+
+// Hash hashes password peppered with the key of the decoded hash, so peppered
+// hashes may be the inner hash of an onion hash
+func (v *pepperedValidator) Hash(password []byte) ([]byte, error) {
+	h, ok := v.v.(Hasher)
+	if !ok {
+		return nil, ErrNotHasher
+	}
+
+	return h.Hash(pepper(v.key, password))
+}
+
+// Replays reports whether the wrapped validator is replayable
+func (v *pepperedValidator) Replays() bool {
+	r, ok := v.v.(Replayer)
+
+	return ok && r.Replays()
+}
+
+var _ Replayer = (*pepperedValidator)(nil)
+
+// EstimatedMemory returns the estimate of the wrapped validator
+func (v *pepperedValidator) EstimatedMemory() int64 {
+	return estimatedMemory(v.v)
+}
+
+var _ MemoryEstimator = (*pepperedValidator)(nil)
+
+var _ Validator = (*pepperedValidator)(nil)
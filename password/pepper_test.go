@@ -0,0 +1,121 @@
+package password_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+var _ = Describe("Pepper", func() {
+	sG := password.NewDefaultSaltGenerator(16)
+	argon2idParams := argon2id.Params{Time: 1, Memory: 64, Threads: 1, KeyLen: 32}
+	pbkdf2Params := pbkdf2.Params{Iter: 1000, KeyLen: 32}
+
+	keys := map[string][]byte{
+		"v1": []byte("retired pepper"),
+		"v2": []byte("current pepper"),
+	}
+
+	var (
+		kr *password.Keyring
+		r  *password.Registry
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		kr, err = password.NewKeyring("v2", keys)
+		Expect(err).ToNot(HaveOccurred())
+
+		r = password.NewRegistry(
+			password.NewPepperedDecoder(argon2id.NewDecoder(), kr),
+			password.NewPepperedDecoder(pbkdf2.NewDecoder(), kr),
+		)
+	})
+
+	hashEncode := func(pFn password.ProviderFn) string {
+		p, err := pFn()
+		Expect(err).ToNot(HaveOccurred())
+
+		eH, err := p.HashEncode([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+
+		return eH
+	}
+
+	It("should record the pepper key id and verify peppered hashes", func() {
+		for _, pFn := range []password.ProviderFn{
+			argon2id.Provide(sG, argon2idParams),
+			pbkdf2.Provide(sG, pbkdf2Params),
+		} {
+			eH := hashEncode(password.Pepper(pFn, kr))
+			Expect(eH).To(ContainSubstring(",keyid=v2$"))
+
+			Expect(r.Verify(eH, []byte("password"))).To(BeTrue(), eH)
+			Expect(r.Verify(eH, []byte("wrong"))).To(BeFalse(), eH)
+		}
+	})
+
+	It("should fail to hash and validate without a fixed salt", func() {
+		p, err := password.Pepper(argon2id.Provide(sG, argon2idParams), kr)()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = p.Hash([]byte("password"))
+		Expect(err).To(MatchError(password.ErrNoSalt))
+
+		_, err = p.Validate([]byte("derivative"), []byte("password"))
+		Expect(err).To(MatchError(password.ErrNoSalt))
+	})
+
+	It("should not verify peppered hashes without the pepper", func() {
+		eH := hashEncode(password.Pepper(argon2id.Provide(sG, argon2idParams), kr))
+
+		Expect(password.NewRegistry(argon2id.NewDecoder()).Verify(eH, []byte("password"))).To(BeFalse())
+	})
+
+	It("should verify hashes peppered with retired keys", func() {
+		old, err := password.NewKeyring("v1", keys)
+		Expect(err).ToNot(HaveOccurred())
+
+		eH := hashEncode(password.Pepper(argon2id.Provide(sG, argon2idParams), old))
+
+		Expect(r.Verify(eH, []byte("password"))).To(BeTrue())
+	})
+
+	It("should verify hashes without pepper", func() {
+		eH := hashEncode(argon2id.Provide(sG, argon2idParams))
+
+		Expect(r.Verify(eH, []byte("password"))).To(BeTrue())
+	})
+
+	It("should fail for unknown pepper keys", func() {
+		eH := hashEncode(password.Pepper(argon2id.Provide(sG, argon2idParams), kr))
+		eH = strings.Replace(eH, "keyid=v2", "keyid=v3", 1)
+
+		_, err := r.Verify(eH, []byte("password"))
+		Expect(err).To(MatchError(password.ErrUnknownKey))
+	})
+
+	It("should flag hashes peppered with retired or no keys for rehashing", func() {
+		old, err := password.NewKeyring("v1", keys)
+		Expect(err).ToNot(HaveOccurred())
+
+		policy := password.NewRehashPolicy(password.Pepper(argon2id.Provide(sG, argon2idParams), kr))
+
+		Expect(policy.NeedsRehash(hashEncode(password.Pepper(argon2id.Provide(sG, argon2idParams), kr)))).To(BeFalse())
+		Expect(policy.NeedsRehash(hashEncode(password.Pepper(argon2id.Provide(sG, argon2idParams), old)))).To(BeTrue())
+		Expect(policy.NeedsRehash(hashEncode(argon2id.Provide(sG, argon2idParams)))).To(BeTrue())
+	})
+
+	It("should reject invalid keyrings", func() {
+		_, err := password.NewKeyring("v3", keys)
+		Expect(err).To(MatchError(password.ErrUnknownKey))
+
+		_, err = password.NewKeyring("v$1", map[string][]byte{"v$1": []byte("pepper")})
+		Expect(err).To(MatchError(password.ErrInvalidKeyID))
+	})
+})
@@ -0,0 +1,179 @@
+package password
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPHC is returned when a string is not a valid PHC formatted hash
+	ErrInvalidPHC = errors.New("invalid phc string")
+	// ErrUnexpectedType is returned when decoding a hash produced by another algorithm
+	ErrUnexpectedType = errors.New("unexpected hash type")
+)
+
+// PHCParam is a single name=value parameter of a PHC string
+type PHCParam struct {
+	Name  string
+	Value string
+}
+
+// PHC is a password hash in the PHC string format:
+//
+//	$<id>[$v=<version>][$<param>=<value>(,<param>=<value>)*][$<salt>[$<hash>]]
+//
+// See https://github.com/P-H-C/phc-string-format/blob/master/phc-sf-spec.md
+type PHC struct {
+	ID string
+	// Version is omitted from the encoded string if it is 0
+	Version int
+	Params  []PHCParam
+	Salt    []byte
+	Hash    []byte
+}
+
+// IsPHC reports whether s looks like a PHC formatted string
+func IsPHC(s string) bool {
+	return strings.HasPrefix(s, "$")
+}
+
+// phcID returns the identifier of the PHC formatted string s without parsing
+// the remaining fields
+func phcID(s string) string {
+	id, _, _ := strings.Cut(strings.TrimPrefix(s, "$"), "$")
+
+	return id
+}
+
+// Param returns the value of the parameter with the given name
+func (p PHC) Param(name string) (string, bool) {
+	for _, pp := range p.Params {
+		if pp.Name == name {
+			return pp.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// UintParam returns the value of the required parameter with the given name
+// parsed as an unsigned integer of the given bit size
+func (p PHC) UintParam(name string, bitSize int) (uint64, error) {
+	v, ok := p.Param(name)
+	if !ok {
+		return 0, ErrInvalidPHC
+	}
+
+	u, err := strconv.ParseUint(v, 10, bitSize)
+	if err != nil {
+		return 0, ErrInvalidPHC
+	}
+
+	return u, nil
+}
+
+// String encodes p in the PHC string format. Salt and hash are encoded using
+// base64 without padding, as required by the specification.
+func (p PHC) String() string {
+	var b strings.Builder
+
+	b.WriteString("$")
+	b.WriteString(p.ID)
+
+	if p.Version != 0 {
+		b.WriteString("$v=")
+		b.WriteString(strconv.Itoa(p.Version))
+	}
+
+	if len(p.Params) > 0 {
+		b.WriteString("$")
+		for i, pp := range p.Params {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(pp.Name)
+			b.WriteString("=")
+			b.WriteString(pp.Value)
+		}
+	}
+
+	if p.Salt != nil {
+		b.WriteString("$")
+		b.WriteString(base64.RawStdEncoding.EncodeToString(p.Salt))
+
+		if p.Hash != nil {
+			b.WriteString("$")
+			b.WriteString(base64.RawStdEncoding.EncodeToString(p.Hash))
+		}
+	}
+
+	return b.String()
+}
+
+// ParsePHC parses a PHC formatted string
+func ParsePHC(s string) (PHC, error) {
+	if !IsPHC(s) {
+		return PHC{}, ErrInvalidPHC
+	}
+
+	fields := strings.Split(s[1:], "$")
+
+	var p PHC
+
+	p.ID = fields[0]
+	if p.ID == "" {
+		return PHC{}, ErrInvalidPHC
+	}
+	fields = fields[1:]
+
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "v=") {
+		v, err := strconv.Atoi(strings.TrimPrefix(fields[0], "v="))
+		if err != nil {
+			return PHC{}, ErrInvalidPHC
+		}
+
+		p.Version = v
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 && strings.Contains(fields[0], "=") {
+		for _, kv := range strings.Split(fields[0], ",") {
+			name, value, ok := strings.Cut(kv, "=")
+			if !ok || name == "" {
+				return PHC{}, ErrInvalidPHC
+			}
+
+			p.Params = append(p.Params, PHCParam{
+				Name:  name,
+				Value: value,
+			})
+		}
+		fields = fields[1:]
+	}
+
+	if len(fields) > 2 {
+		return PHC{}, ErrInvalidPHC
+	}
+
+	if len(fields) > 0 {
+		salt, err := base64.RawStdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return PHC{}, ErrInvalidPHC
+		}
+
+		p.Salt = salt
+	}
+
+	if len(fields) > 1 {
+		h, err := base64.RawStdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return PHC{}, ErrInvalidPHC
+		}
+
+		p.Hash = h
+	}
+
+	return p, nil
+}
@@ -0,0 +1,76 @@
+package password_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+)
+
+var _ = Describe("PHC", func() {
+	It("should encode all fields in order", func() {
+		p := password.PHC{
+			ID:      "argon2id",
+			Version: 19,
+			Params: []password.PHCParam{
+				{Name: "m", Value: "65536"},
+				{Name: "t", Value: "3"},
+				{Name: "p", Value: "4"},
+			},
+			Salt: []byte("somesalt"),
+			Hash: []byte("somehash"),
+		}
+
+		Expect(p.String()).To(Equal("$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$c29tZWhhc2g"))
+	})
+
+	It("should parse what it encodes", func() {
+		s := "$pbkdf2-sha512$i=1000$c29tZXNhbHQ$c29tZWhhc2g"
+
+		p, err := password.ParsePHC(s)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.ID).To(Equal("pbkdf2-sha512"))
+		Expect(p.Version).To(Equal(0))
+		Expect(p.Salt).To(Equal([]byte("somesalt")))
+		Expect(p.Hash).To(Equal([]byte("somehash")))
+
+		i, err := p.UintParam("i", 32)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(i).To(Equal(uint64(1000)))
+
+		Expect(p.String()).To(Equal(s))
+	})
+
+	It("should parse a string consisting of the id only", func() {
+		p, err := password.ParsePHC("$argon2id")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.ID).To(Equal("argon2id"))
+		Expect(p.Salt).To(BeNil())
+		Expect(p.Hash).To(BeNil())
+	})
+
+	It("should reject malformed strings", func() {
+		for _, s := range []string{
+			"",
+			"argon2id$v=19",
+			"$",
+			"$argon2id$v=x",
+			"$argon2id$m=1,=2$c2FsdA",
+			"$argon2id$m=1$not base64$c2FsdA",
+			"$argon2id$m=1$c2FsdA$aGFzaA$extra",
+		} {
+			_, err := password.ParsePHC(s)
+			Expect(err).To(MatchError(password.ErrInvalidPHC), s)
+		}
+	})
+
+	It("should report missing or invalid integer params", func() {
+		p, err := password.ParsePHC("$argon2id$m=abc")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = p.UintParam("m", 32)
+		Expect(err).To(MatchError(password.ErrInvalidPHC))
+
+		_, err = p.UintParam("t", 32)
+		Expect(err).To(MatchError(password.ErrInvalidPHC))
+	})
+})
@@ -0,0 +1,59 @@
+package policy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+// prefixLen is the length of the hex encoded SHA-1 prefix used for k-anonymity
+const prefixLen = 5
+
+// RangeChecker checks passwords against a local copy of a k-anonymity SHA-1
+// range dataset such as the Pwned Passwords range API. The dataset is a
+// directory with one file per 5 character uppercase hex SHA-1 prefix, each
+// file consisting of lines of the form <SUFFIX>:<COUNT>, the format of the
+// range API's responses.
+type RangeChecker struct {
+	fsys fs.FS
+}
+
+func NewRangeChecker(fsys fs.FS) *RangeChecker {
+	return &RangeChecker{
+		fsys: fsys,
+	}
+}
+
+// IsBreached reports whether the SHA-1 hash of password is listed in the
+// dataset. A missing prefix file is treated as no match.
+func (c *RangeChecker) IsBreached(password string) (bool, error) {
+	h := sha1.Sum([]byte(password))
+	hH := strings.ToUpper(hex.EncodeToString(h[:]))
+
+	prefix, suffix := hH[:prefixLen], hH[prefixLen:]
+
+	f, err := c.fsys.Open(prefix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		lS, _, _ := strings.Cut(strings.TrimSpace(s.Text()), ":")
+
+		if strings.EqualFold(lS, suffix) {
+			return true, nil
+		}
+	}
+
+	return false, s.Err()
+}
+
+var _ BreachedChecker = (*RangeChecker)(nil)
@@ -0,0 +1,289 @@
+// Package policy validates passwords against a configurable strength policy
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/theater-improrama/go-utils/validator"
+)
+
+// ErrPolicyViolation is matched by the Violations returned for passwords
+// violating a policy
+var ErrPolicyViolation = errors.New("password policy violation")
+
+// minForbiddenLen is the minimum length of forbidden substrings to be checked,
+// shorter ones would reject too many passwords
+const minForbiddenLen = 3
+
+type ViolationCode string
+
+const (
+	ViolationTooShort   ViolationCode = "too_short"
+	ViolationTooLong    ViolationCode = "too_long"
+	ViolationLowEntropy ViolationCode = "low_entropy"
+	ViolationForbidden  ViolationCode = "forbidden_substring"
+	ViolationRepeat     ViolationCode = "repeated_characters"
+	ViolationSequence   ViolationCode = "character_sequence"
+	ViolationBreached   ViolationCode = "breached"
+)
+
+// Violation is a single rule of a policy a password violates
+type Violation struct {
+	Code    ViolationCode
+	Message string
+}
+
+// Violations are all rules of a policy a password violates
+type Violations []Violation
+
+func (v Violations) Error() string {
+	msgs := make([]string, len(v))
+	for i, vi := range v {
+		msgs[i] = vi.Message
+	}
+
+	return ErrPolicyViolation.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+func (v Violations) Is(target error) bool {
+	return target == ErrPolicyViolation
+}
+
+// Has reports whether v contains a violation with the given code
+func (v Violations) Has(c ViolationCode) bool {
+	for _, vi := range v {
+		if vi.Code == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BreachedChecker reports whether a password is known from data breaches
+type BreachedChecker interface {
+	IsBreached(password string) (bool, error)
+}
+
+// Policy is a password policy. Rules with a zero value are disabled. Lengths
+// are counted in characters (runes), not bytes.
+type Policy struct {
+	MinLength int
+	MaxLength int
+	// MinEntropy is the minimum estimated entropy in bits, see EstimateEntropy
+	MinEntropy float64
+	// MaxRepeat is the maximum number of consecutive identical characters
+	MaxRepeat int
+	// MaxSequence is the maximum length of ascending or descending character
+	// sequences like "abcd" or "4321"
+	MaxSequence int
+	// Breached rejects passwords known from data breaches
+	Breached BreachedChecker
+}
+
+// Check returns the violations of password. Forbidden substrings like the
+// username or email are matched case-insensitively, forbidden substrings
+// shorter than 3 characters are ignored. An error is only returned if the
+// breached password check fails.
+func (p *Policy) Check(password string, forbidden ...string) (Violations, error) {
+	var vs Violations
+
+	l := utf8.RuneCountInString(password)
+
+	if p.MinLength > 0 && l < p.MinLength {
+		vs = append(vs, Violation{
+			Code:    ViolationTooShort,
+			Message: fmt.Sprintf("must be at least %d characters long", p.MinLength),
+		})
+	}
+
+	if p.MaxLength > 0 && l > p.MaxLength {
+		vs = append(vs, Violation{
+			Code:    ViolationTooLong,
+			Message: fmt.Sprintf("must be at most %d characters long", p.MaxLength),
+		})
+	}
+
+	if p.MinEntropy > 0 && EstimateEntropy(password) < p.MinEntropy {
+		vs = append(vs, Violation{
+			Code:    ViolationLowEntropy,
+			Message: "is too easy to guess",
+		})
+	}
+
+	lP := strings.ToLower(password)
+	for _, f := range forbidden {
+		if utf8.RuneCountInString(f) < minForbiddenLen {
+			continue
+		}
+
+		if strings.Contains(lP, strings.ToLower(f)) {
+			vs = append(vs, Violation{
+				Code:    ViolationForbidden,
+				Message: "must not contain personal information",
+			})
+			break
+		}
+	}
+
+	if p.MaxRepeat > 0 && longestRepeat(password) > p.MaxRepeat {
+		vs = append(vs, Violation{
+			Code:    ViolationRepeat,
+			Message: fmt.Sprintf("must not repeat a character more than %d times in a row", p.MaxRepeat),
+		})
+	}
+
+	if p.MaxSequence > 0 && longestSequence(lP) > p.MaxSequence {
+		vs = append(vs, Violation{
+			Code:    ViolationSequence,
+			Message: fmt.Sprintf("must not contain sequences longer than %d characters", p.MaxSequence),
+		})
+	}
+
+	if p.Breached != nil {
+		b, err := p.Breached.IsBreached(password)
+		if err != nil {
+			return nil, err
+		}
+
+		if b {
+			vs = append(vs, Violation{
+				Code:    ViolationBreached,
+				Message: "is known from a data breach",
+			})
+		}
+	}
+
+	return vs, nil
+}
+
+// Password returns a validatable password checked against p
+func (p *Policy) Password(password string, forbidden ...string) Password {
+	return Password{
+		policy:    p,
+		password:  password,
+		forbidden: forbidden,
+	}
+}
+
+// Password is a password to be validated against a policy
+type Password struct {
+	policy    *Policy
+	password  string
+	forbidden []string
+}
+
+// Validate returns the Violations of the password, which match
+// ErrPolicyViolation, or the error of the breached password check
+func (p Password) Validate() error {
+	vs, err := p.policy.Check(p.password, p.forbidden...)
+	if err != nil {
+		return err
+	}
+
+	if len(vs) > 0 {
+		return vs
+	}
+
+	return nil
+}
+
+var _ validator.Validator = Password{}
+
+// EstimateEntropy returns a rough estimate of the entropy of password in bits,
+// assuming each character was chosen at random from the union of the
+// character classes (lower, upper, digits, symbols, others) it contains
+func EstimateEntropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < utf8.RuneSelf && unicode.IsPrint(r):
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	pool := 0
+	for _, c := range []struct {
+		present bool
+		size    int
+	}{
+		{lower, 26},
+		{upper, 26},
+		{digit, 10},
+		{symbol, 33},
+		{other, 100},
+	} {
+		if c.present {
+			pool += c.size
+		}
+	}
+
+	if pool == 0 {
+		return 0
+	}
+
+	return float64(utf8.RuneCountInString(password)) * math.Log2(float64(pool))
+}
+
+// longestRepeat returns the length of the longest run of identical characters
+func longestRepeat(s string) int {
+	longest, run := 0, 0
+	var prev rune
+
+	for i, r := range []rune(s) {
+		if i > 0 && r == prev {
+			run++
+		} else {
+			run = 1
+		}
+
+		longest = max(longest, run)
+		prev = r
+	}
+
+	return longest
+}
+
+// longestSequence returns the length of the longest run of characters
+// ascending or descending by one
+func longestSequence(s string) int {
+	rs := []rune(s)
+	if len(rs) == 0 {
+		return 0
+	}
+
+	longest, run := 1, 1
+	var step rune
+
+	for i := 1; i < len(rs); i++ {
+		d := rs[i] - rs[i-1]
+
+		switch {
+		case (d == 1 || d == -1) && d == step:
+			run++
+		case d == 1 || d == -1:
+			run = 2
+		default:
+			run = 1
+		}
+
+		step = d
+		longest = max(longest, run)
+	}
+
+	return longest
+}
@@ -0,0 +1,85 @@
+package policy_test
+
+import (
+	"errors"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password/policy"
+)
+
+type failingChecker struct{}
+
+func (failingChecker) IsBreached(string) (bool, error) {
+	return false, errors.New("dataset unavailable")
+}
+
+var _ = Describe("Policy", func() {
+	breached := policy.NewRangeChecker(fstest.MapFS{
+		"21BD1": &fstest.MapFile{Data: []byte(
+			"0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n" +
+				"2DC183F740EE76F27B78EB39C8AD972A757:52579\r\n",
+		)},
+	})
+
+	p := &policy.Policy{
+		MinLength:   8,
+		MaxLength:   64,
+		MinEntropy:  40,
+		MaxRepeat:   3,
+		MaxSequence: 4,
+		Breached:    breached,
+	}
+
+	It("should accept a strong password", func() {
+		Expect(p.Password("correct horse battery staple").Validate()).To(Succeed())
+	})
+
+	DescribeTable("should report violations",
+		func(pw string, code policy.ViolationCode) {
+			vs, err := p.Check(pw, "jdoe", "jdoe@example.com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vs.Has(code)).To(BeTrue(), "%v", vs)
+		},
+		Entry("too short", "aB3$", policy.ViolationTooShort),
+		Entry("too long", string(make([]byte, 65)), policy.ViolationTooLong),
+		Entry("low entropy", "aaaaaaab", policy.ViolationLowEntropy),
+		Entry("username", "my name is JDoe!", policy.ViolationForbidden),
+		Entry("email", "jdoe@example.com1", policy.ViolationForbidden),
+		Entry("repeats", "Zxcv!!!!2024q", policy.ViolationRepeat),
+		Entry("ascending sequence", "Xq!abcde2024", policy.ViolationSequence),
+		Entry("descending sequence", "Xq!98765zzq", policy.ViolationSequence),
+		Entry("breached", "P@ssw0rd", policy.ViolationBreached),
+	)
+
+	It("should count characters instead of bytes", func() {
+		vs, err := (&policy.Policy{MaxLength: 4}).Check("äöüß")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vs).To(BeEmpty())
+	})
+
+	It("should ignore short forbidden substrings", func() {
+		vs, err := (&policy.Policy{}).Check("password with ab", "ab")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vs).To(BeEmpty())
+	})
+
+	It("should return violations matching ErrPolicyViolation", func() {
+		err := p.Password("short").Validate()
+		Expect(err).To(MatchError(policy.ErrPolicyViolation))
+
+		var vs policy.Violations
+		Expect(errors.As(err, &vs)).To(BeTrue())
+		Expect(vs.Has(policy.ViolationTooShort)).To(BeTrue())
+	})
+
+	It("should return errors of the breached password check", func() {
+		err := (&policy.Policy{Breached: failingChecker{}}).Password("password").Validate()
+		Expect(err).To(MatchError("dataset unavailable"))
+	})
+
+	It("should treat missing range files as not breached", func() {
+		Expect(breached.IsBreached("not in the dataset")).To(BeFalse())
+	})
+})
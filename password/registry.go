@@ -0,0 +1,166 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrUnknownType is returned when no decoder is registered for the type of an encoded hash
+	ErrUnknownType = errors.New("unknown hash type")
+	// ErrVerifyOnly is returned by verify-only providers of legacy formats when hashing new passwords
+	ErrVerifyOnly = errors.New("verify-only hash type")
+	// ErrNoSalt is returned by Hash and Validate of providers without a fixed
+	// salt, whose hashes are only verifiable through their encoded form
+	ErrNoSalt = errors.New("hasher has no fixed salt")
+)
+
+// Decoder rebuilds a Validator from an encoded hash
+type Decoder interface {
+	Typer
+	// DecodeValidator decodes eH and returns the derivative together with a
+	// Validator configured with the salt and params embedded in eH
+	DecodeValidator(eH string) ([]byte, Validator, error)
+}
+
+// Identifier is implemented by types whose encoded hashes carry identifiers
+// other than their type, e.g. the $2a$ and $2b$ prefixes of bcrypt hashes
+type Identifier interface {
+	Identifiers() []string
+}
+
+// identifies reports whether id identifies hashes encoded by t
+func identifies(t Typer, id string) bool {
+	if t.Type() == id {
+		return true
+	}
+
+	i, ok := t.(Identifier)
+	if !ok {
+		return false
+	}
+
+	for _, iID := range i.Identifiers() {
+		if iID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Registry verifies encoded hashes of any registered type
+type Registry struct {
+	mu    sync.RWMutex
+	ds    map[string]Decoder
+	ids   map[string]string
+	order []string
+}
+
+func NewRegistry(ds ...Decoder) *Registry {
+	r := &Registry{
+		ds:  make(map[string]Decoder),
+		ids: make(map[string]string),
+	}
+
+	for _, d := range ds {
+		r.Register(d)
+	}
+
+	return r
+}
+
+// Register registers d by its type and, if d implements Identifier, by its
+// identifiers, replacing any decoder previously registered for the same type
+func (r *Registry) Register(d Decoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := d.Type()
+	if _, ok := r.ds[t]; !ok {
+		r.order = append(r.order, t)
+	}
+
+	r.ds[t] = d
+
+	if i, ok := d.(Identifier); ok {
+		for _, id := range i.Identifiers() {
+			r.ids[id] = t
+		}
+	}
+}
+
+// Decode detects the type of eH and decodes it with the matching decoder.
+// Hashes in the PHC string format are detected by their identifier, all other
+// encodings are offered to the registered decoders in registration order.
+func (r *Registry) Decode(eH string) ([]byte, Validator, error) {
+	ds := r.decoders(eH)
+
+	if IsPHC(eH) {
+		if len(ds) == 0 {
+			return nil, nil, ErrUnknownType
+		}
+
+		return ds[0].DecodeValidator(eH)
+	}
+
+	for _, d := range ds {
+		dK, v, err := d.DecodeValidator(eH)
+		if err != nil {
+			continue
+		}
+
+		return dK, v, nil
+	}
+
+	return nil, nil, ErrUnknownType
+}
+
+// decoders returns the decoders eH is offered to. The lock is not held while
+// decoding, so decoders may decode nested hashes with the registry.
+func (r *Registry) decoders(eH string) []Decoder {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if IsPHC(eH) {
+		id := phcID(eH)
+		if t, ok := r.ids[id]; ok {
+			id = t
+		}
+
+		d, ok := r.ds[id]
+		if !ok {
+			return nil
+		}
+
+		return []Decoder{d}
+	}
+
+	ds := make([]Decoder, len(r.order))
+	for i, t := range r.order {
+		ds[i] = r.ds[t]
+	}
+
+	return ds
+}
+
+// Verify reports whether password matches the encoded hash eH
+func (r *Registry) Verify(eH string, password []byte) (bool, error) {
+	return r.VerifyContext(context.Background(), eH, password)
+}
+
+// VerifyContext reports whether password matches the encoded hash eH. ctx is
+// passed on to validators implementing ContextValidator.
+func (r *Registry) VerifyContext(ctx context.Context, eH string, password []byte) (bool, error) {
+	dK, v, err := r.Decode(eH)
+	if err != nil {
+		return false, err
+	}
+
+	if cV, ok := v.(ContextValidator); ok {
+		return cV.ValidateContext(ctx, dK, password)
+	}
+
+	return v.Validate(dK, password)
+}
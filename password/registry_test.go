@@ -0,0 +1,77 @@
+package password_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+var _ = Describe("Registry", func() {
+	var r *password.Registry
+
+	BeforeEach(func() {
+		r = password.NewRegistry(
+			argon2id.NewDecoder(),
+			pbkdf2.NewDecoder(),
+		)
+	})
+
+	hashEncode := func(pFn password.ProviderFn, pw string) string {
+		p, err := pFn()
+		Expect(err).ToNot(HaveOccurred())
+
+		eH, err := p.HashEncode([]byte(pw))
+		Expect(err).ToNot(HaveOccurred())
+
+		return eH
+	}
+
+	It("should verify hashes of every registered type", func() {
+		sG := password.NewDefaultSaltGenerator(16)
+
+		for _, pFn := range []password.ProviderFn{
+			argon2id.Provide(sG, argon2id.Params{Time: 1, Memory: 64, Threads: 1, KeyLen: 32}),
+			pbkdf2.Provide(sG, pbkdf2.Params{Iter: 1000, KeyLen: 32}),
+		} {
+			eH := hashEncode(pFn, "password")
+
+			Expect(r.Verify(eH, []byte("password"))).To(BeTrue(), eH)
+			Expect(r.Verify(eH, []byte("wrong"))).To(BeFalse(), eH)
+		}
+	})
+
+	It("should reject hashes of unregistered types", func() {
+		_, err := r.Verify("$scrypt$ln=15,r=8,p=1$c2FsdA$aGFzaA", []byte("password"))
+		Expect(err).To(MatchError(password.ErrUnknownType))
+
+		_, err = r.Verify("not a hash", []byte("password"))
+		Expect(err).To(MatchError(password.ErrUnknownType))
+	})
+
+	It("should replace decoders registered for the same type", func() {
+		r = password.NewRegistry()
+
+		eH := hashEncode(pbkdf2.Provide(
+			password.NewDefaultSaltGenerator(16),
+			pbkdf2.Params{Iter: 1000, KeyLen: 32},
+		), "password")
+
+		_, err := r.Verify(eH, []byte("password"))
+		Expect(err).To(MatchError(password.ErrUnknownType))
+
+		r.Register(pbkdf2.NewDecoder())
+		r.Register(pbkdf2.NewDecoder())
+
+		Expect(r.Verify(eH, []byte("password"))).To(BeTrue())
+	})
+
+	It("should detect the type of hashes in the legacy JSON encoding", func() {
+		// {"derivative":..,"salt":..,"params":{"key_len":32,"iter":1000}} for "password"
+		legacy := "eyJkZXJpdmF0aXZlIjoiTnpFMWNuRkpjalZrV0U5V1VIQkNhSEZ4YzNWbmJEQXpOM3BVTldKWFNsUlhXVzFhZEVsalN6aG9RVDA9Iiwic2FsdCI6Ill6SkdjMlJJVG1oaVNGSjZXVmQ0TUdNeVJuTmtRVDA5IiwicGFyYW1zIjp7ImtleV9sZW4iOjMyLCJpdGVyIjoxMDAwfX0="
+
+		Expect(r.Verify(legacy, []byte("password"))).To(BeTrue())
+		Expect(r.Verify(legacy, []byte("wrong"))).To(BeFalse())
+	})
+})
@@ -0,0 +1,48 @@
+package password
+
+// RehashChecker reports whether an encoded hash should be replaced by a fresh
+// hash of the same password
+type RehashChecker interface {
+	NeedsRehash(eH string) (bool, error)
+}
+
+// RehashPolicy compares encoded hashes against the currently preferred
+// provider. Call NeedsRehash after a successful login and, if it reports true,
+// store a new hash of the just validated password.
+type RehashPolicy struct {
+	preferred ProviderFn
+}
+
+func NewRehashPolicy(preferred ProviderFn) *RehashPolicy {
+	return &RehashPolicy{
+		preferred: preferred,
+	}
+}
+
+// NeedsRehash reports whether eH was produced by another algorithm than the
+// preferred provider, uses the legacy non PHC encoding, or was produced with
+// params differing from the preferred provider's. Params are only compared if
+// the preferred provider implements RehashChecker.
+func (r *RehashPolicy) NeedsRehash(eH string) (bool, error) {
+	p, err := r.preferred()
+	if err != nil {
+		return false, err
+	}
+
+	if !IsPHC(eH) {
+		return true, nil
+	}
+
+	if !identifies(p, phcID(eH)) {
+		return true, nil
+	}
+
+	c, ok := p.(RehashChecker)
+	if !ok {
+		return false, nil
+	}
+
+	return c.NeedsRehash(eH)
+}
+
+var _ RehashChecker = (*RehashPolicy)(nil)
@@ -0,0 +1,51 @@
+package password_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+var _ = Describe("RehashPolicy", func() {
+	sG := password.NewDefaultSaltGenerator(16)
+	current := argon2id.Params{Time: 1, Memory: 64, Threads: 1, KeyLen: 32}
+
+	hashEncode := func(pFn password.ProviderFn) string {
+		p, err := pFn()
+		Expect(err).ToNot(HaveOccurred())
+
+		eH, err := p.HashEncode([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+
+		return eH
+	}
+
+	policy := password.NewRehashPolicy(argon2id.Provide(sG, current))
+
+	It("should not flag hashes produced with the preferred params", func() {
+		eH := hashEncode(argon2id.Provide(sG, current))
+
+		Expect(policy.NeedsRehash(eH)).To(BeFalse())
+	})
+
+	It("should flag hashes produced with outdated params", func() {
+		outdated := current
+		outdated.Memory = 32
+
+		eH := hashEncode(argon2id.Provide(sG, outdated))
+
+		Expect(policy.NeedsRehash(eH)).To(BeTrue())
+	})
+
+	It("should flag hashes produced by another algorithm", func() {
+		eH := hashEncode(pbkdf2.Provide(sG, pbkdf2.Params{Iter: 1000, KeyLen: 32}))
+
+		Expect(policy.NeedsRehash(eH)).To(BeTrue())
+	})
+
+	It("should flag hashes in the legacy JSON encoding", func() {
+		Expect(policy.NeedsRehash("eyJkZXJpdmF0aXZlIjoiIn0=")).To(BeTrue())
+	})
+})
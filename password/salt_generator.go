@@ -1,8 +1,13 @@
 package password
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
 	"errors"
+	"io"
+	mrand "math/rand/v2"
+	"sync"
 )
 
 type DefaultSaltGenerator struct {
@@ -31,3 +36,72 @@ func NewDefaultSaltGenerator(length int) *DefaultSaltGenerator {
 		length: length,
 	}
 }
+
+// ReaderSaltGenerator generates salts read from an io.Reader, e.g. a seeded
+// deterministic source in tests
+type ReaderSaltGenerator struct {
+	mu     sync.Mutex
+	r      io.Reader
+	length int
+}
+
+func (r *ReaderSaltGenerator) Generate() ([]byte, error) {
+	s := make([]byte, r.length)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := io.ReadFull(r.r, s); err != nil {
+		return nil, errors.Join(errSaltGenerationFailed, err)
+	}
+
+	return s, nil
+}
+
+func NewReaderSaltGenerator(r io.Reader, length int) *ReaderSaltGenerator {
+	return &ReaderSaltGenerator{
+		r:      r,
+		length: length,
+	}
+}
+
+// NewSeededSaltGenerator returns a salt generator producing the same sequence
+// of salts for the same seed. It is only meant for tests, the salts are
+// predictable.
+func NewSeededSaltGenerator(seed string, length int) *ReaderSaltGenerator {
+	return NewReaderSaltGenerator(mrand.NewChaCha8(sha256.Sum256([]byte(seed))), length)
+}
+
+// FixedSaltGenerator cycles through a fixed sequence of salts. It is only
+// meant for tests.
+type FixedSaltGenerator struct {
+	mu    sync.Mutex
+	salts [][]byte
+	next  int
+}
+
+func (f *FixedSaltGenerator) Generate() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.salts) == 0 {
+		return nil, errSaltGenerationFailed
+	}
+
+	s := f.salts[f.next]
+	f.next = (f.next + 1) % len(f.salts)
+
+	return bytes.Clone(s), nil
+}
+
+func NewFixedSaltGenerator(salts ...[]byte) *FixedSaltGenerator {
+	return &FixedSaltGenerator{
+		salts: salts,
+	}
+}
+
+var (
+	_ SaltGenerator = (*DefaultSaltGenerator)(nil)
+	_ SaltGenerator = (*ReaderSaltGenerator)(nil)
+	_ SaltGenerator = (*FixedSaltGenerator)(nil)
+)
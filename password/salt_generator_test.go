@@ -0,0 +1,90 @@
+package password_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/argon2id"
+	"github.com/theater-improrama/go-utils/password/bcrypt"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+	"github.com/theater-improrama/go-utils/password/scrypt"
+)
+
+var _ = Describe("SaltGenerator", func() {
+	generate := func(sG password.SaltGenerator) []byte {
+		s, err := sG.Generate()
+		Expect(err).ToNot(HaveOccurred())
+
+		return s
+	}
+
+	It("should generate the same salts for the same seed", func() {
+		a := password.NewSeededSaltGenerator("seed", 16)
+		b := password.NewSeededSaltGenerator("seed", 16)
+		c := password.NewSeededSaltGenerator("other", 16)
+
+		first := generate(a)
+		Expect(first).To(HaveLen(16))
+		Expect(generate(b)).To(Equal(first))
+		Expect(generate(c)).ToNot(Equal(first))
+		Expect(generate(a)).ToNot(Equal(first))
+	})
+
+	It("should fail when the reader is exhausted", func() {
+		sG := password.NewReaderSaltGenerator(bytes.NewReader([]byte("short")), 16)
+
+		_, err := sG.Generate()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should cycle through fixed salts", func() {
+		sG := password.NewFixedSaltGenerator([]byte("salt-one"), []byte("salt-two"))
+
+		Expect(generate(sG)).To(Equal([]byte("salt-one")))
+		Expect(generate(sG)).To(Equal([]byte("salt-two")))
+		Expect(generate(sG)).To(Equal([]byte("salt-one")))
+
+		_, err := password.NewFixedSaltGenerator().Generate()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should produce reproducible hashes with test params", func() {
+		r := password.NewRegistry(
+			argon2id.NewDecoder(),
+			pbkdf2.NewDecoder(),
+			scrypt.NewDecoder(),
+			bcrypt.NewDecoder(),
+		)
+
+		for _, pFn := range []func(password.SaltGenerator) password.ProviderFn{
+			func(sG password.SaltGenerator) password.ProviderFn {
+				return argon2id.Provide(sG, argon2id.TestParams())
+			},
+			func(sG password.SaltGenerator) password.ProviderFn { return pbkdf2.Provide(sG, pbkdf2.TestParams()) },
+			func(sG password.SaltGenerator) password.ProviderFn { return scrypt.Provide(sG, scrypt.TestParams()) },
+		} {
+			var eHs []string
+			for range 2 {
+				p, err := pFn(password.NewSeededSaltGenerator("seed", 16))()
+				Expect(err).ToNot(HaveOccurred())
+
+				eH, err := p.HashEncode([]byte("password"))
+				Expect(err).ToNot(HaveOccurred())
+
+				eHs = append(eHs, eH)
+			}
+
+			Expect(eHs[0]).To(Equal(eHs[1]))
+			Expect(r.Verify(eHs[0], []byte("password"))).To(BeTrue())
+		}
+
+		p, err := bcrypt.Provide(bcrypt.TestParams())()
+		Expect(err).ToNot(HaveOccurred())
+
+		eH, err := p.HashEncode([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.Verify(eH, []byte("password"))).To(BeTrue())
+	})
+})
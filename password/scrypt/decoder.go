@@ -0,0 +1,25 @@
+package scrypt
+
+import "github.com/theater-improrama/go-utils/password"
+
+// ScryptDecoder rebuilds validators from encoded hashes for use with a password.Registry
+type ScryptDecoder struct{}
+
+func NewDecoder() *ScryptDecoder {
+	return &ScryptDecoder{}
+}
+
+func (d *ScryptDecoder) Type() string {
+	return Type
+}
+
+func (d *ScryptDecoder) DecodeValidator(eH string) ([]byte, password.Validator, error) {
+	dK, salt, p, err := Decode(eH)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dK, New(salt, p), nil
+}
+
+var _ password.Decoder = (*ScryptDecoder)(nil)
@@ -0,0 +1,16 @@
+package scrypt_test
+
+import (
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/passwordtest"
+	"github.com/theater-improrama/go-utils/password/scrypt"
+)
+
+var _ = passwordtest.DescribeProvider(
+	"Scrypt",
+	scrypt.Provide(
+		password.NewDefaultSaltGenerator(16),
+		scrypt.Params{N: 16, R: 8, P: 1, KeyLen: 32},
+	),
+	scrypt.NewDecoder(),
+)
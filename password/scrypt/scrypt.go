@@ -0,0 +1,229 @@
+package scrypt
+
+import (
+	"crypto/subtle"
+	"errors"
+	"math/bits"
+	"strconv"
+
+	"github.com/theater-improrama/go-utils/password"
+	"golang.org/x/crypto/scrypt"
+)
+
+const Type = "scrypt"
+
+// ErrInvalidCost is returned when encoding a hash whose cost parameter N is not a power of two
+var ErrInvalidCost = errors.New("scrypt cost must be a power of two")
+
+// Provide returns providers generating a fresh salt with sG for every encoded
+// hash, so a single provider may be reused and shared between goroutines. Hash
+// and Validate fail with password.ErrNoSalt.
+func Provide(sG password.SaltGenerator, p Params) password.ProviderFn {
+	return func() (password.Provider, error) {
+		v := New(
+			nil,
+			p,
+		)
+		v.sG = sG
+
+		return v, nil
+	}
+}
+
+type ScryptHasherValidator struct {
+	sG     password.SaltGenerator
+	salt   []byte
+	params Params
+}
+
+type Params struct {
+	// N is the CPU/memory cost parameter, it must be a power of two
+	N      int `json:"n"`
+	R      int `json:"r"`
+	P      int `json:"p"`
+	KeyLen int `json:"key_len"`
+}
+
+// TestParams returns N=2, r=1, p=1, for tests only
+func TestParams() Params {
+	return Params{N: 2, R: 1, P: 1, KeyLen: 32}
+}
+
+func New(
+	salt []byte,
+	params Params,
+) *ScryptHasherValidator {
+	return &ScryptHasherValidator{
+		params: params,
+		salt:   salt,
+	}
+}
+
+// Decode parses a scrypt hash in the PHC string format
+// ($scrypt$ln=<log2(N)>,r=<r>,p=<p>$<salt>$<hash>)
+func Decode(eH string) ([]byte, []byte, Params, error) {
+	phc, err := password.ParsePHC(eH)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	if phc.ID != Type {
+		return nil, nil, Params{}, password.ErrUnexpectedType
+	}
+
+	ln, err := phc.UintParam("ln", 5)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	r, err := phc.UintParam("r", 31)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	p, err := phc.UintParam("p", 31)
+	if err != nil {
+		return nil, nil, Params{}, err
+	}
+
+	if ln < 1 || r < 1 || p < 1 || len(phc.Salt) == 0 || len(phc.Hash) == 0 {
+		return nil, nil, Params{}, password.ErrInvalidPHC
+	}
+
+	return phc.Hash, phc.Salt, Params{
+		N:      1 << ln,
+		R:      int(r),
+		P:      int(p),
+		KeyLen: len(phc.Hash),
+	}, nil
+}
+
+// Encode encodes a scrypt hash in the PHC string format. The key length is
+// implied by the length of the derivative.
+func Encode(dK []byte, salt []byte, p Params) (string, error) {
+	if p.N <= 1 || bits.OnesCount(uint(p.N)) != 1 {
+		return "", ErrInvalidCost
+	}
+
+	phc := password.PHC{
+		ID: Type,
+		Params: []password.PHCParam{
+			{Name: "ln", Value: strconv.Itoa(bits.TrailingZeros(uint(p.N)))},
+			{Name: "r", Value: strconv.Itoa(p.R)},
+			{Name: "p", Value: strconv.Itoa(p.P)},
+		},
+		Salt: salt,
+		Hash: dK,
+	}
+
+	return phc.String(), nil
+}
+
+// HashEncode hashes password with a fresh salt if sG is set, the fixed salt
+// otherwise
+func (v *ScryptHasherValidator) HashEncode(password []byte) (string, error) {
+	salt := v.salt
+
+	if v.sG != nil {
+		s, err := v.sG.Generate()
+		if err != nil {
+			return "", err
+		}
+
+		salt = s
+	}
+
+	return v.HashEncodeSalt(password, salt)
+}
+
+// HashEncodeSalt hashes password with the caller-supplied salt and encodes the hash
+func (v *ScryptHasherValidator) HashEncodeSalt(password []byte, salt []byte) (string, error) {
+	dK, err := v.HashSalt(password, salt)
+	if err != nil {
+		return "", err
+	}
+
+	return Encode(dK, salt, v.params)
+}
+
+func (v *ScryptHasherValidator) Type() string {
+	return Type
+}
+
+// HashSalt hashes password with the caller-supplied salt
+func (v *ScryptHasherValidator) HashSalt(password []byte, salt []byte) ([]byte, error) {
+	return scrypt.Key(
+		password,
+		salt,
+		v.params.N,
+		v.params.R,
+		v.params.P,
+		v.params.KeyLen,
+	)
+}
+
+// Hash hashes pw with the fixed salt
+func (v *ScryptHasherValidator) Hash(pw []byte) ([]byte, error) {
+	if len(v.salt) == 0 {
+		return nil, password.ErrNoSalt
+	}
+
+	return v.HashSalt(pw, v.salt)
+}
+
+func (v *ScryptHasherValidator) Validate(dK []byte, password []byte) (bool, error) {
+	pDk, err := v.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	kL := int32(len(dK))
+	pKL := int32(len(pDk))
+
+	if subtle.ConstantTimeEq(kL, pKL) == 0 {
+		return false, nil
+	}
+
+	if subtle.ConstantTimeCompare(dK, pDk) != 1 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// EstimatedMemory returns the memory in bytes a single hash operation allocates
+func (v *ScryptHasherValidator) EstimatedMemory() int64 {
+	return 128 * int64(v.params.R) * (int64(v.params.N) + int64(v.params.P))
+}
+
+var _ password.MemoryEstimator = (*ScryptHasherValidator)(nil)
+
+// NeedsRehash reports whether eH was produced with params differing from v's
+func (v *ScryptHasherValidator) NeedsRehash(eH string) (bool, error) {
+	_, _, hP, err := Decode(eH)
+	if err != nil {
+		return false, err
+	}
+
+	return hP != v.params, nil
+}
+
+var _ password.RehashChecker = (*ScryptHasherValidator)(nil)
+
+// Replays reports whether v hashes with a fixed salt
+func (v *ScryptHasherValidator) Replays() bool {
+	return len(v.salt) > 0
+}
+
+var _ password.Replayer = (*ScryptHasherValidator)(nil)
+
+// EncodePHC encodes dK with the salt and params of v
+func (v *ScryptHasherValidator) EncodePHC(dK []byte) (string, error) {
+	return Encode(dK, v.salt, v.params)
+}
+
+var _ password.PHCEncoder = (*ScryptHasherValidator)(nil)
+
+var _ password.SaltHasher = (*ScryptHasherValidator)(nil)
+
+var _ password.Provider = (*ScryptHasherValidator)(nil)
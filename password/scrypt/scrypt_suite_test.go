@@ -0,0 +1,13 @@
+package scrypt_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestScrypt(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scrypt Suite")
+}
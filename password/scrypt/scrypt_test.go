@@ -0,0 +1,60 @@
+package scrypt_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/password"
+	"github.com/theater-improrama/go-utils/password/scrypt"
+)
+
+var _ = Describe("Scrypt", func() {
+	params := scrypt.Params{
+		N:      16,
+		R:      8,
+		P:      1,
+		KeyLen: 32,
+	}
+
+	It("should encode hashes in the PHC string format", func() {
+		v := scrypt.New([]byte("somesalt"), params)
+
+		eH, err := v.HashEncode([]byte("password"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(eH).To(Equal("$scrypt$ln=4,r=8,p=1$c29tZXNhbHQ$7xe5L3Roj67jYaBKf3ePT2Y6rVHHGUWO44Z8iz+O6PQ"))
+	})
+
+	It("should decode and validate encoded hashes", func() {
+		dK, salt, p, err := scrypt.Decode("$scrypt$ln=4,r=8,p=1$c29tZXNhbHQ$7xe5L3Roj67jYaBKf3ePT2Y6rVHHGUWO44Z8iz+O6PQ")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(salt).To(Equal([]byte("somesalt")))
+		Expect(p).To(Equal(params))
+
+		v := scrypt.New(salt, p)
+		Expect(v.Validate(dK, []byte("password"))).To(BeTrue())
+		Expect(v.Validate(dK, []byte("wrong"))).To(BeFalse())
+	})
+
+	It("should refuse to encode a cost that is not a power of two", func() {
+		_, err := scrypt.Encode([]byte("hash"), []byte("salt"), scrypt.Params{N: 15, R: 8, P: 1})
+		Expect(err).To(MatchError(scrypt.ErrInvalidCost))
+	})
+
+	It("should reject hashes of other algorithms", func() {
+		_, _, _, err := scrypt.Decode("$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$c29tZWhhc2g")
+		Expect(err).To(MatchError(password.ErrUnexpectedType))
+	})
+
+	DescribeTable("should reject malformed PHC strings",
+		func(eH string) {
+			_, _, _, err := scrypt.Decode(eH)
+			Expect(err).To(MatchError(password.ErrInvalidPHC))
+		},
+		Entry("missing salt and hash", "$scrypt$ln=4,r=8,p=1"),
+		Entry("empty hash", "$scrypt$ln=4,r=8,p=1$c2FsdA$"),
+		Entry("empty salt and hash", "$scrypt$ln=4,r=8,p=1$$"),
+		Entry("empty salt", "$scrypt$ln=4,r=8,p=1$$aGFzaA"),
+		Entry("zero cost", "$scrypt$ln=0,r=8,p=1$c2FsdA$aGFzaA"),
+		Entry("zero block size", "$scrypt$ln=4,r=0,p=1$c2FsdA$aGFzaA"),
+		Entry("zero parallelism", "$scrypt$ln=4,r=8,p=0$c2FsdA$aGFzaA"),
+	)
+})
@@ -0,0 +1,139 @@
+// Package token generates secure random tokens like password reset tokens,
+// API keys, invite codes and TOTP secrets
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"github.com/theater-improrama/go-utils/password"
+)
+
+// CodeAlphabet is the alphabet of human-friendly codes. It excludes the
+// ambiguous characters 0, 1, I and O and, having 32 characters, maps random
+// bytes without bias.
+const CodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// ErrInvalidLength is returned when requesting a token of non-positive length
+var ErrInvalidLength = errors.New("invalid token length")
+
+// Generator generates tokens from an entropy source
+type Generator struct {
+	r io.Reader
+}
+
+// New returns a generator reading entropy from r. Pass a deterministic reader
+// in tests only, use Default otherwise.
+func New(r io.Reader) *Generator {
+	return &Generator{
+		r: r,
+	}
+}
+
+// Default returns a generator reading entropy from crypto/rand
+func Default() *Generator {
+	return New(rand.Reader)
+}
+
+// Bytes returns n random bytes
+func (g *Generator) Bytes(n int) ([]byte, error) {
+	if n <= 0 {
+		return nil, ErrInvalidLength
+	}
+
+	bs := make([]byte, n)
+	if _, err := io.ReadFull(g.r, bs); err != nil {
+		return nil, err
+	}
+
+	return bs, nil
+}
+
+// Base64URL returns n random bytes encoded as URL-safe base64 without padding,
+// suited for password reset tokens and API keys
+func (g *Generator) Base64URL(n int) (string, error) {
+	bs, err := g.Bytes(n)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bs), nil
+}
+
+// Base32 returns n random bytes encoded as base32 without padding, the
+// encoding of TOTP secrets
+func (g *Generator) Base32(n int) (string, error) {
+	bs, err := g.Bytes(n)
+	if err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(bs), nil
+}
+
+// Hex returns n random bytes encoded as lowercase hex
+func (g *Generator) Hex(n int) (string, error) {
+	bs, err := g.Bytes(n)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bs), nil
+}
+
+// Code returns a human-friendly code of length characters of CodeAlphabet,
+// suited for invite and recovery codes. Each character carries 5 bits of
+// entropy.
+func (g *Generator) Code(length int) (string, error) {
+	bs, err := g.Bytes(length)
+	if err != nil {
+		return "", err
+	}
+
+	for i, b := range bs {
+		bs[i] = CodeAlphabet[int(b)%len(CodeAlphabet)]
+	}
+
+	return string(bs), nil
+}
+
+// SaltGenerator returns a password.SaltGenerator generating salts of length
+// bytes with g
+func (g *Generator) SaltGenerator(length int) password.SaltGenerator {
+	return &saltGenerator{
+		g:      g,
+		length: length,
+	}
+}
+
+type saltGenerator struct {
+	g      *Generator
+	length int
+}
+
+func (s *saltGenerator) Generate() ([]byte, error) {
+	return s.g.Bytes(s.length)
+}
+
+var _ password.SaltGenerator = (*saltGenerator)(nil)
+
+// Hash returns the digest of token to be stored at rest instead of the token.
+// Tokens carry enough entropy to not require a slow password hash, so the
+// digest may be used to look up the stored token.
+func Hash(token string) []byte {
+	h := sha256.Sum256([]byte(token))
+
+	return h[:]
+}
+
+// Verify reports whether token matches the stored digest, comparing in
+// constant time
+func Verify(token string, hashed []byte) bool {
+	return subtle.ConstantTimeCompare(Hash(token), hashed) == 1
+}
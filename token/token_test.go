@@ -0,0 +1,60 @@
+package token_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/theater-improrama/go-utils/token"
+)
+
+var _ = Describe("Token", func() {
+	seeded := func() *token.Generator {
+		return token.New(bytes.NewReader([]byte{
+			0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+			0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff,
+		}))
+	}
+
+	It("should encode tokens deterministically for a given entropy source", func() {
+		Expect(seeded().Hex(4)).To(Equal("00010203"))
+		Expect(seeded().Base64URL(16)).To(Equal("AAECAwQFBgf4-fr7_P3-_w"))
+		Expect(seeded().Base32(5)).To(Equal("AAAQEAYE"))
+		Expect(seeded().Code(16)).To(Equal("23456789STUVWXYZ"))
+	})
+
+	It("should only use unambiguous characters in codes", func() {
+		c, err := token.Default().Code(256)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(strings.ContainsAny(c, "01IO")).To(BeFalse())
+	})
+
+	It("should fail when the entropy source is exhausted", func() {
+		_, err := seeded().Bytes(17)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject invalid lengths", func() {
+		_, err := token.Default().Bytes(0)
+		Expect(err).To(MatchError(token.ErrInvalidLength))
+	})
+
+	It("should generate salts", func() {
+		s, err := seeded().SaltGenerator(4).Generate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s).To(Equal([]byte{0x00, 0x01, 0x02, 0x03}))
+	})
+
+	It("should verify tokens against their stored digest", func() {
+		t, err := token.Default().Base64URL(32)
+		Expect(err).ToNot(HaveOccurred())
+
+		h := token.Hash(t)
+
+		Expect(token.Verify(t, h)).To(BeTrue())
+		Expect(token.Verify(t+"x", h)).To(BeFalse())
+		Expect(token.Verify(t, h[:16])).To(BeFalse())
+	})
+})
@@ -0,0 +1,62 @@
+// passwordcalibrate benchmarks password hashing on the current host and prints recommended params
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/theater-improrama/go-utils/password/argon2id"
+	"github.com/theater-improrama/go-utils/password/pbkdf2"
+)
+
+func main() {
+	var (
+		algorithm string
+		target    time.Duration
+		maxMemory uint
+		digest    string
+	)
+
+	flag.StringVar(&algorithm, "algorithm", argon2id.Type, "Algorithm to calibrate, argon2id or pbkdf2")
+	flag.DurationVar(&target, "target", 500*time.Millisecond, "Target duration of hashing a single password")
+	flag.UintVar(&maxMemory, "max-memory", 64*1024, "Maximum memory in KiB used by argon2id")
+	flag.StringVar(&digest, "digest", pbkdf2.DigestSha512, "Digest used by pbkdf2, sha1, sha256 or sha512")
+	flag.Parse()
+
+	switch algorithm {
+	case argon2id.Type:
+		if maxMemory > math.MaxUint32 {
+			fatalf("max-memory must be at most %d KiB", uint32(math.MaxUint32))
+		}
+
+		p, err := argon2id.Calibrate(target, uint32(maxMemory))
+		if err != nil {
+			fatalf("calibrate argon2id: %v", err)
+		}
+
+		fmt.Printf(
+			"argon2id.Params{Time: %d, Memory: %d, Threads: %d, KeyLen: %d}\n",
+			p.Time, p.Memory, p.Threads, p.KeyLen,
+		)
+	case "pbkdf2":
+		p, err := pbkdf2.Calibrate(target, digest)
+		if err != nil {
+			fatalf("calibrate pbkdf2: %v", err)
+		}
+
+		fmt.Printf(
+			"pbkdf2.Params{KeyLen: %d, Iter: %d, Digest: %q}\n",
+			p.KeyLen, p.Iter, p.Digest,
+		)
+	default:
+		fatalf("unknown algorithm %q, use argon2id or pbkdf2", algorithm)
+	}
+}
+
+func fatalf(format string, a ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+	os.Exit(2)
+}